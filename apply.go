@@ -3,17 +3,20 @@ package k8t
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/discovery"
-	memory "k8s.io/client-go/discovery/cached"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/restmapper"
 )
 
 // options for GetWithOpts function
@@ -22,9 +25,49 @@ type ApplyOpts struct {
 	// context.Background() will be used
 	Context context.Context
 
-	// namespace where to apply resource. If it's not set, the cluster's
-	// test namespace will be used. This is ignored for cluster-wide resources
+	// namespace where to apply resource. If it's not set, the namespace
+	// from object's metadata or the cluster's test namespace will be used.
+	// Objects with different namespace in metadata are rejected. This is
+	// ignored for cluster-wide resources
 	Namespace string
+
+	// if it's set, the objects are sent to server with 'dryRun=All'. Server
+	// process the request (admission, validation, defaulting) as usual, but
+	// nothing is persisted. Note CRDs are not created with dry-run, so custom
+	// resources of new kinds cannot be applied in the same dry-run.
+	DryRun bool
+
+	// name of the field manager used for server-side apply. If it's not
+	// set, the 'k8t' will be used. Different managers are handy when you
+	// want to simulate different actors, e.g. user and controller.
+	FieldManager string
+
+	// if it's set, the apply takes ownership of the fields owned by other
+	// managers. If it's not set, the apply fails with ConflictError when
+	// some field is owned by another manager.
+	Force bool
+}
+
+// returns options for server-side apply patch
+func (opts ApplyOpts) patchOptions() metav1.PatchOptions {
+	patchOpts := metav1.PatchOptions{
+		FieldManager: opts.FieldManager,
+	}
+
+	if patchOpts.FieldManager == "" {
+		patchOpts.FieldManager = "k8t"
+	}
+
+	if opts.DryRun {
+		patchOpts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	if opts.Force {
+		force := true
+		patchOpts.Force = &force
+	}
+
+	return patchOpts
 }
 
 // Apply manifest on given path
@@ -36,76 +79,254 @@ func (c *Cluster) ApplyFile(path string) error {
 	return c.Apply(string(data))
 }
 
+// Apply all manifests ('.yaml', '.yml' and '.json' files) in given
+// directory and its subdirectories. Files are applied in lexical order.
+func (c *Cluster) ApplyDir(path string) error {
+	return c.ApplyFS(os.DirFS(path), ".")
+}
+
+// Apply all manifests matching glob pattern from given filesystem. It's
+// handy for fixtures embedded into test binary:
+//
+//	//go:embed testdata
+//	var fixtures embed.FS
+//
+//	cluster.ApplyFS(fixtures, "testdata/*.yaml")
+//
+// Directories matching the pattern are walked recursively.
+func (c *Cluster) ApplyFS(fsys fs.FS, glob string) error {
+	_, err := c.ApplyFSWithOpts(fsys, glob, ApplyOpts{})
+	return err
+}
+
+// More verbose version of ApplyFS() function where you can pass your own
+// context or set namespace.
+func (c *Cluster) ApplyFSWithOpts(fsys fs.FS, glob string, opts ApplyOpts) (ApplyResult, error) {
+	objs, err := loadManifestsFromFS(fsys, glob)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+
+	return c.applyObjects(objs, opts)
+}
+
+// Render manifest template with given data and apply it. The template is
+// using Go's text/template syntax with sprig functions:
+//
+//	cluster.ApplyTemplate(`
+//	apiVersion: v1
+//	kind: ConfigMap
+//	metadata:
+//	  name: {{ .Name }}
+//	data:
+//	  image: {{ .Image | quote }}
+//	`, map[string]any{"Name": "config", "Image": "busybox:1.36"})
+func (c *Cluster) ApplyTemplate(tmpl string, data any) error {
+	_, err := c.ApplyTemplateWithOpts(tmpl, data, ApplyOpts{})
+	return err
+}
+
+// Render manifest template on given path with given data and apply it.
+func (c *Cluster) ApplyTemplateFile(path string, data any) error {
+	tmpl, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	yml, err := renderTemplate(filepath.Base(path), string(tmpl), data)
+	if err != nil {
+		return err
+	}
+
+	return c.Apply(yml)
+}
+
+// More verbose version of ApplyTemplate() function where you can pass your
+// own context or set namespace.
+func (c *Cluster) ApplyTemplateWithOpts(tmpl string, data any, opts ApplyOpts) (ApplyResult, error) {
+	yml, err := renderTemplate("manifest", tmpl, data)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+
+	return c.ApplyWithOpts(yml, opts)
+}
+
 // Same as kubectl apply. Function apply any YAML into given cluster,
 // into default test namespace.
 func (c *Cluster) Apply(yml string) error {
-	return c.ApplyWithOpts(yml, ApplyOpts{})
+	_, err := c.ApplyWithOpts(yml, ApplyOpts{})
+	return err
 }
 
 // More verbose version of Apply() function where you can pass your own
-// context or set namespace
-func (c *Cluster) ApplyWithOpts(yml string, opts ApplyOpts) error {
+// context or set namespace.
+//
+// The YAML can contain multiple documents separated by '---', or 'kind: List'
+// objects. All the objects are applied, even if some of them fail. The
+// returned error contains all the objects which failed.
+//
+// Objects are applied in dependency order, namespaces and CRDs first,
+// then RBAC, configuration and workloads, custom resources at the end.
+// Objects without namespace in metadata are applied into opts.Namespace,
+// or into cluster's test namespace. Objects with explicit namespace are
+// kept in their namespace, but if opts.Namespace is set, it must match.
+//
+// The result contains live objects returned by the server, together with
+// information if they were created, configured or unchanged. If some objects
+// fail, the result still contains the objects which were applied.
+func (c *Cluster) ApplyWithOpts(yml string, opts ApplyOpts) (ApplyResult, error) {
+	// Decode YAML manifest into unstructured.Unstructured objects
+	objs, err := DecodeManifest(yml)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+
+	return c.applyObjects(objs, opts)
+}
+
+// apply all given objects with server-side-apply
+func (c *Cluster) applyObjects(objs []*unstructured.Unstructured, opts ApplyOpts) (ApplyResult, error) {
 
 	ctx := opts.Context
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	namespace := opts.Namespace
-	if namespace == "" {
-		namespace = c.testNamespace
+	// Prepare the dynamic client
+	dyn, err := dynamic.NewForConfig(c.restConfig)
+	if err != nil {
+		return ApplyResult{}, err
 	}
 
-	// Decode YAML manifest into unstructured.Unstructured
-	decUnstructured := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
-	obj := &unstructured.Unstructured{}
-	_, gvk, err := decUnstructured.Decode([]byte(yml), nil, obj)
-	if err != nil {
-		return err
+	result := ApplyResult{Objects: make([]AppliedObject, 0, len(objs))}
+	errs := make([]error, 0)
+	crds := make([]*unstructured.Unstructured, 0)
+	failedKinds := make(map[schema.GroupKind]bool)
+	for _, obj := range sortForApply(objs) {
+
+		// new kinds can be used only when their CRDs are established
+		if len(crds) > 0 && !isCRD(obj) {
+			failed, err := c.waitForCRDs(ctx, crds)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			for gk := range failed {
+				failedKinds[gk] = true
+			}
+			crds = crds[:0]
+		}
+
+		// custom resources of kinds which are not established cannot be
+		// applied, other objects are still applied
+		if failedKinds[obj.GroupVersionKind().GroupKind()] {
+			errs = append(errs, fmt.Errorf("%s: CustomResourceDefinition of the kind is not established", objectRef(obj)))
+			continue
+		}
+
+		existing, live, err := applyObject(ctx, dyn, c.restMapper, obj, opts.Namespace, c.testNamespace, opts.patchOptions())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", objectRef(obj), err))
+			continue
+		}
+
+		result.Objects = append(result.Objects, AppliedObject{
+			Object:  live,
+			Outcome: applyOutcome(existing, live),
+		})
+
+		// with dry-run, CRDs are not created and we cannot wait for them
+		if isCRD(obj) && !opts.DryRun {
+			crds = append(crds, obj)
+		}
 	}
 
-	// Prepare a RESTMapper and find GVR
-	dc, err := discovery.NewDiscoveryClientForConfig(c.restConfig)
-	if err != nil {
-		return err
+	// manifest with CRDs only, we still want them ready for the caller
+	if len(crds) > 0 {
+		_, err := c.waitForCRDs(ctx, crds)
+		if err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
-	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
-	if err != nil {
-		return err
+	return result, errors.Join(errs...)
+}
+
+// wait until all given CRDs are established and reset the REST mapper, so
+// the new kinds can be resolved. Function returns kinds defined by CRDs
+// which are not established, together with the error.
+func (c *Cluster) waitForCRDs(ctx context.Context, crds []*unstructured.Unstructured) (map[schema.GroupKind]bool, error) {
+	errs := make([]error, 0)
+	failed := make(map[schema.GroupKind]bool)
+	for _, crd := range crds {
+		err := c.WaitForWithOpts(crdIsEstablished(crd.GetName()), WaitForOpts{Context: ctx})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("CustomResourceDefinition/%s is not established: %w", crd.GetName(), err))
+			failed[crdGroupKind(crd)] = true
+		}
 	}
 
-	// Prepare the dynamic client
-	dyn, err := dynamic.NewForConfig(c.restConfig)
+	c.restMapper.Reset()
+	return failed, errors.Join(errs...)
+}
+
+// returns group and kind defined by given CRD
+func crdGroupKind(crd *unstructured.Unstructured) schema.GroupKind {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	return schema.GroupKind{Group: group, Kind: kind}
+}
+
+// apply single object into namespace from options, or into object's
+// namespace, or into default namespace. Function returns the object as
+// it existed before apply (nil if it didn't exist) and the live object
+// returned by server.
+func applyObject(ctx context.Context, dyn dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured, namespace, defaultNamespace string, patchOpts metav1.PatchOptions) (*unstructured.Unstructured, *unstructured.Unstructured, error) {
+	mapping, namespace, err := objectMapping(mapper, obj, namespace, defaultNamespace)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Obtain REST interface for the GVR
-	var dr dynamic.ResourceInterface
-	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
-		// namespaced resources are placed into test Namespace
-		dr = dyn.Resource(mapping.Resource).Namespace(namespace)
-	} else {
-		// for cluster-wide resources
-		dr = dyn.Resource(mapping.Resource)
-	}
+	dr := resourceInterface(dyn, mapping, namespace)
 
 	// Marshal object into JSON
 	data, err := json.Marshal(obj)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	// Create or Update the object with server-side-apply
-	_, err = dr.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
-		FieldManager: "k8t",
-	})
+	// Get existing object, so we know what apply will do
+	existing, err := dr.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		existing = nil
+	} else if err != nil {
+		return nil, nil, err
+	}
 
+	// Create or Update the object with server-side-apply
+	live, err := dr.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, patchOpts)
 	if err != nil {
-		return err
-	} else {
-		return nil
+		return nil, nil, asConflictError(obj, err)
+	}
+
+	return existing, live, nil
+}
+
+// Server-side apply doesn't tell us if object was created or configured,
+// so the outcome is determined by comparing the existing object with the
+// live one. Server changes the resourceVersion only when apply changes the
+// object. For dry-run, the resourceVersion is never changed, that's why
+// we're comparing also the content.
+func applyOutcome(existing, live *unstructured.Unstructured) ApplyOutcome {
+	switch {
+	case existing == nil:
+		return Created
+	case existing.GetResourceVersion() != live.GetResourceVersion():
+		return Configured
+	case len(diffObjects(existing, live)) > 0:
+		return Configured
+	default:
+		return Unchanged
 	}
 }
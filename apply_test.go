@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/sn3d/k8t"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func Test_Apply(t *testing.T) {
@@ -27,3 +28,144 @@ func Test_Apply(t *testing.T) {
 	}
 
 }
+
+func Test_ApplyMultiDoc(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I apply manifest with multiple documents
+	err = cluster.ApplyFile("testdata/multi-doc.yaml")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: all the objects exist
+	err = cluster.WaitFor(k8t.ResourceExist("v1", "ConfigMap", "multi-doc-config"))
+	if err != nil {
+		t.FailNow()
+	}
+
+	err = cluster.WaitFor(k8t.ResourceExist("v1", "Service", "multi-doc-service"))
+	if err != nil {
+		t.FailNow()
+	}
+
+	err = cluster.WaitFor(k8t.ResourceExist("apps/v1", "Deployment", "multi-doc"))
+	if err != nil {
+		t.FailNow()
+	}
+}
+
+func Test_ApplyCRD(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I apply manifest where custom resource is before its CRD
+	err = cluster.ApplyFile("testdata/crd-test.yaml")
+
+	// THEN: apply must end with no error
+	if err != nil {
+		t.FailNow()
+	}
+
+	// AND: the custom resource exists
+	_, err = cluster.Get("k8t.sn3d.com/v1", "Greeting", "hello")
+	if err != nil {
+		t.FailNow()
+	}
+}
+
+func Test_ApplyResult(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	// AND: applied service
+	data, err := os.ReadFile("testdata/simple-service.yaml")
+	if err != nil {
+		t.FailNow()
+	}
+
+	_, err = cluster.ApplyWithOpts(string(data), k8t.ApplyOpts{})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I apply the same service again
+	result, err := cluster.ApplyWithOpts(string(data), k8t.ApplyOpts{})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: the service is unchanged
+	if len(result.Objects) != 1 || result.Objects[0].Outcome != k8t.Unchanged {
+		t.FailNow()
+	}
+
+	// AND: the live object contains defaulted fields
+	svc := result.Find("v1", "Service", cluster.TestNamespace(), "echo-service")
+	if svc == nil || svc.GetUID() == "" {
+		t.FailNow()
+	}
+
+	clusterIP, _, _ := unstructured.NestedString(svc.Object, "spec", "clusterIP")
+	if clusterIP == "" {
+		t.FailNow()
+	}
+}
+
+func Test_ApplyDryRun(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I apply new config map with dry-run
+	result, err := cluster.ApplyWithOpts(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: dry-run-config
+data:
+  greeting: hello
+`, k8t.ApplyOpts{DryRun: true})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: the result says the config map would be created
+	if len(result.Objects) != 1 || result.Objects[0].Outcome != k8t.Created {
+		t.FailNow()
+	}
+
+	// AND: the config map is not created
+	_, err = cluster.Get("v1", "ConfigMap", "dry-run-config")
+	if err == nil {
+		t.FailNow()
+	}
+}
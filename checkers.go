@@ -2,6 +2,8 @@ package k8t
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -9,6 +11,19 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
+// checkers return error wrapping ErrTerminal when the condition cannot be met
+// anymore, e.g. pod in 'CrashLoopBackOff' or job which exceeded its backoff
+// limit. You can test it with errors.Is(err, k8t.ErrTerminal).
+var ErrTerminal = errors.New("terminal failure")
+
+// returns error wrapping ErrTerminal with formatted description. Use it in
+// your own checkers, when waiting doesn't make sense anymore:
+//
+//	return false, k8t.Terminalf("order %s was rejected: %s", name, reason)
+func Terminalf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrTerminal, fmt.Sprintf(format, args...))
+}
+
 // enables the WaitFor() function to receive and execute custom
 // checking functions, allowing the caller to define their own
 // conditions for waiting on a specific resource or state to be reached.
@@ -45,8 +60,14 @@ func (c *Cluster) WaitFor(check Checker) error {
 //   - reach timeout limit defined in opts
 //
 // The function returns nil if the given checking function met the condition.
-// If we reach the timeout or some error occured during checking, the function
-// returns error.
+// Errors returned by checker are considered transient (e.g. resource doesn't
+// exist yet, API server is not reachable) and the checking continues. If we
+// reach the timeout, the function returns error together with the last
+// checker's error.
+//
+// When checker returns error wrapping ErrTerminal, the condition cannot be
+// met anymore and the function returns the error immediately, without
+// waiting the whole timeout.
 func (c *Cluster) WaitForWithOpts(check Checker, opts WaitForOpts) error {
 
 	ctx := opts.Context
@@ -64,10 +85,20 @@ func (c *Cluster) WaitForWithOpts(check Checker, opts WaitForOpts) error {
 		interval = 2 * time.Second
 	}
 
-	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(innerCtx context.Context) (done bool, err error) {
-		return check(innerCtx, c)
+	var lastErr error
+	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(innerCtx context.Context) (bool, error) {
+		done, err := check(innerCtx, c)
+		if err != nil && !errors.Is(err, ErrTerminal) {
+			lastErr = err
+			return false, nil
+		}
+		return done, err
 	})
 
+	if err != nil && lastErr != nil && !errors.Is(err, ErrTerminal) {
+		return fmt.Errorf("%w, last error: %v", err, lastErr)
+	}
+
 	return err
 }
 
@@ -105,7 +136,9 @@ func ResourceNotExist(apiVersion, kind, name string) Checker {
 
 // check if given pod is running, that means it's in `Running` phase.
 // The function expect also namespace. If you provide empty string, then
-// cluster's default test namespace will be used.
+// cluster's default test namespace will be used. If the pod cannot start,
+// e.g. it's in 'ImagePullBackOff' or 'CrashLoopBackOff', the checker returns
+// error wrapping ErrTerminal.
 func PodIsRunning(namespace, name string) Checker {
 
 	return func(ctx context.Context, c *Cluster) (bool, error) {
@@ -119,6 +152,10 @@ func PodIsRunning(namespace, name string) Checker {
 			return false, err
 		}
 
+		if err := podTerminalError(pod); err != nil {
+			return false, err
+		}
+
 		if pod.Status.Phase == corev1.PodRunning {
 			return true, nil
 		}
@@ -0,0 +1,67 @@
+package k8t
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_WaitForRetriesTransientErrors(t *testing.T) {
+	cluster := &Cluster{}
+	opts := WaitForOpts{
+		Timeout:  time.Second,
+		Interval: 10 * time.Millisecond,
+	}
+
+	// GIVEN: checker which fails twice with transient error and then is met
+	calls := 0
+	flaky := func(context.Context, *Cluster) (bool, error) {
+		calls++
+		if calls <= 2 {
+			return false, errors.New("connection refused")
+		}
+		return true, nil
+	}
+
+	// THEN: the waiting continues until the condition is met
+	if err := cluster.WaitForWithOpts(flaky, opts); err != nil || calls != 3 {
+		t.FailNow()
+	}
+
+	// AND: on timeout, the last error is reported
+	opts.Timeout = 50 * time.Millisecond
+	err := cluster.WaitForWithOpts(constantChecker(false, errors.New("connection refused")), opts)
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.FailNow()
+	}
+}
+
+func Test_WaitForAbortsOnTerminalError(t *testing.T) {
+	cluster := &Cluster{}
+	opts := WaitForOpts{
+		Timeout:  time.Minute,
+		Interval: 10 * time.Millisecond,
+	}
+
+	// GIVEN: checker which detects terminal failure
+	calls := 0
+	stuck := func(context.Context, *Cluster) (bool, error) {
+		calls++
+		return false, Terminalf("pod %s is in %s", "my-pod", "CrashLoopBackOff")
+	}
+
+	// WHEN: I wait for the condition
+	start := time.Now()
+	err := cluster.WaitForWithOpts(stuck, opts)
+
+	// THEN: the waiting ends immediately with terminal error
+	if !errors.Is(err, ErrTerminal) || calls != 1 || time.Since(start) > time.Second {
+		t.FailNow()
+	}
+
+	if err.Error() != "terminal failure: pod my-pod is in CrashLoopBackOff" {
+		t.Fatal(err)
+	}
+}
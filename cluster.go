@@ -2,10 +2,15 @@ package k8t
 
 import (
 	"errors"
+	"fmt"
 	"os"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	memory "k8s.io/client-go/discovery/cached"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
@@ -23,12 +28,19 @@ type Cluster struct {
 	restConfig *rest.Config
 
 	// often we need k8s client, it's initialized by New()
-	k8sClient *kubernetes.Clientset
+	k8sClient kubernetes.Interface
 
 	// When we test things, we often use separated namespace, so we don't mess up
 	// other things This special namespace is used by all the functions automatically
 	testNamespace string
 
+	// namespace created by NewTestNamespace(). It's deleted by Cleanup()
+	// and it's empty for clusters which don't own the test namespace
+	ephemeralNamespace string
+
+	// cached discovery client, shared with restMapper
+	discoveryClient discovery.CachedDiscoveryInterface
+
 	restMapper *restmapper.DeferredDiscoveryRESTMapper
 }
 
@@ -64,7 +76,8 @@ func New(apiConfig *api.Config) (*Cluster, error) {
 	if err != nil {
 		return nil, err
 	}
-	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+	cachedDiscoveryClient := memory.NewMemCacheClient(discoveryClient)
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscoveryClient)
 
 	testNamespace := getDefaultNamespace(apiConfig)
 	if testNamespace == "" {
@@ -72,11 +85,12 @@ func New(apiConfig *api.Config) (*Cluster, error) {
 	}
 
 	c := &Cluster{
-		apiConfig:     apiConfig,
-		restConfig:    restCfg,
-		k8sClient:     k8sClient,
-		restMapper:    restMapper,
-		testNamespace: getDefaultNamespace(apiConfig),
+		apiConfig:       apiConfig,
+		restConfig:      restCfg,
+		k8sClient:       k8sClient,
+		discoveryClient: cachedDiscoveryClient,
+		restMapper:      restMapper,
+		testNamespace:   getDefaultNamespace(apiConfig),
 	}
 
 	return c, nil
@@ -105,3 +119,69 @@ func getDefaultNamespace(apiConfig *api.Config) string {
 
 	return ctx.Namespace
 }
+
+// resolve apiVersion and kind into REST interface of the resource in
+// given namespace. The namespace is ignored for cluster-wide resources.
+func (c *Cluster) resourceFor(apiVersion, kind, namespace string) (dynamic.ResourceInterface, error) {
+	// we need to parse and convert apiVersion and Kind into
+	// GroupVersionResource
+	gvk := schema.FromAPIVersionAndKind(apiVersion, kind)
+
+	rm, err := c.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := dynamic.NewForConfig(c.restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceInterface(d, rm, namespace), nil
+}
+
+// returns REST mapping and namespace of given object. See objectNamespace()
+// for the namespace rules, the namespace is empty for cluster-wide resources.
+func objectMapping(mapper meta.RESTMapper, obj *unstructured.Unstructured, namespace, defaultNamespace string) (*meta.RESTMapping, string, error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return mapping, "", nil
+	}
+
+	namespace, err = objectNamespace(obj, namespace, defaultNamespace)
+	if err != nil {
+		return nil, "", err
+	}
+	return mapping, namespace, nil
+}
+
+// returns namespace of given object. The namespace from options is
+// authoritative, the object with different namespace in metadata is
+// rejected. If options have no namespace, the object's namespace is used,
+// or the default one.
+func objectNamespace(obj *unstructured.Unstructured, namespace, defaultNamespace string) (string, error) {
+	switch {
+	case namespace != "" && obj.GetNamespace() != "" && obj.GetNamespace() != namespace:
+		return "", fmt.Errorf("the namespace '%s' of the object doesn't match the namespace '%s'", obj.GetNamespace(), namespace)
+	case namespace != "":
+		return namespace, nil
+	case obj.GetNamespace() != "":
+		return obj.GetNamespace(), nil
+	default:
+		return defaultNamespace, nil
+	}
+}
+
+// returns REST interface for given mapping. Namespaced resources are placed
+// into given namespace, the namespace is ignored for cluster-wide resources
+func resourceInterface(dyn dynamic.Interface, mapping *meta.RESTMapping, namespace string) dynamic.ResourceInterface {
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return dyn.Resource(mapping.Resource).Namespace(namespace)
+	}
+	return dyn.Resource(mapping.Resource)
+}
@@ -3,6 +3,7 @@ package k8t
 import (
 	"testing"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -22,3 +23,58 @@ func Test_getDefaultNamespace(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func Test_WithNamespace(t *testing.T) {
+	// GIVEN: some cluster with default test namespace
+	cluster := &Cluster{testNamespace: "team-b"}
+
+	// WHEN: I derive cluster for another namespace
+	derived := cluster.WithNamespace("team-x")
+
+	// THEN: derived cluster is using the new namespace
+	if derived.TestNamespace() != "team-x" {
+		t.FailNow()
+	}
+
+	// AND: the original cluster is untouched
+	if cluster.TestNamespace() != "team-b" {
+		t.FailNow()
+	}
+
+	// AND: derived cluster doesn't own the namespace, cleanup does nothing
+	if derived.Cleanup() != nil {
+		t.FailNow()
+	}
+}
+
+func Test_objectNamespace(t *testing.T) {
+	// GIVEN: object without namespace
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+	// THEN: namespace from options is used, or the default one
+	if ns, err := objectNamespace(obj, "opts", "default"); ns != "opts" || err != nil {
+		t.FailNow()
+	}
+
+	if ns, err := objectNamespace(obj, "", "default"); ns != "default" || err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: object has explicit namespace
+	obj.SetNamespace("my-ns")
+
+	// THEN: it's used instead of the default one
+	if ns, err := objectNamespace(obj, "", "default"); ns != "my-ns" || err != nil {
+		t.FailNow()
+	}
+
+	// AND: it's accepted when it's the same as in options
+	if ns, err := objectNamespace(obj, "my-ns", "default"); ns != "my-ns" || err != nil {
+		t.FailNow()
+	}
+
+	// AND: it's rejected when it's different from options
+	if _, err := objectNamespace(obj, "opts", "default"); err == nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,143 @@
+package k8t
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Optional options for Consistently function
+type ConsistentlyOpts struct {
+	// here you can pass your context. It it's not set, the default
+	// context.Background() will be used
+	Context context.Context
+
+	// duration of the window in which the condition must be met all the
+	// time. If it's not set, the default 30 seconds will be used
+	Duration time.Duration
+
+	// represents the duration between invocations of the checker function
+	// If it's not set, the defauls 2 seconds will be used
+	Interval time.Duration
+}
+
+// returns checker which is met when all the given checkers are met. The
+// checkers are invoked in given order and the first unmet checker or error
+// stops the evaluation.
+func All(checks ...Checker) Checker {
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		for _, check := range checks {
+			ok, err := check(ctx, c)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+}
+
+// returns checker which is met when at least one of the given checkers is
+// met. The checkers are invoked in given order and the first met checker
+// stops the evaluation. Errors don't stop the evaluation, so e.g.
+// Any(JobSucceeded(..), JobFailed(..)) is met by both outcomes. The errors
+// are returned only when none of the checkers is met. The error is terminal
+// only if all the checkers ended with terminal error, otherwise some of them
+// can be still met.
+func Any(checks ...Checker) Checker {
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		errs := make([]error, 0)
+		terminal := 0
+		for _, check := range checks {
+			ok, err := check(ctx, c)
+			if ok {
+				return true, nil
+			}
+
+			if err != nil {
+				errs = append(errs, err)
+			}
+
+			if errors.Is(err, ErrTerminal) {
+				terminal++
+			}
+		}
+
+		err := errors.Join(errs...)
+		if err != nil && terminal > 0 && terminal < len(checks) {
+			// keep the message, but drop the ErrTerminal from the chain
+			return false, errors.New(err.Error())
+		}
+		return false, err
+	}
+}
+
+// returns checker which is met when the given checker is not met. Error
+// wrapping ErrTerminal means the given checker cannot be met anymore, so
+// the negated one is met, e.g. Not(JobSucceeded(...)) on failed job. Other
+// errors are not negated, they're returned as they are.
+func Not(check Checker) Checker {
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		ok, err := check(ctx, c)
+		if errors.Is(err, ErrTerminal) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return !ok, nil
+	}
+}
+
+// the function will check if system met given condition during the whole
+// window, e.g. the pod stays running for 30 seconds:
+//
+//	err := cluster.Consistently(k8t.PodIsRunning("", "my-pod"), k8t.ConsistentlyOpts{
+//	   Duration: 30 * time.Second,
+//	})
+//
+// The checking is triggered every n seconds defined by interval in opts.
+// The function returns error as soon as the condition is not met, or some
+// error occured during checking. It returns nil if the condition was met
+// during the whole window.
+func (c *Cluster) Consistently(check Checker, opts ConsistentlyOpts) error {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	duration := opts.Duration
+	if duration == 0 {
+		duration = 30 * time.Second
+	}
+
+	interval := opts.Interval
+	if interval == 0 {
+		interval = 2 * time.Second
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := check(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return fmt.Errorf("condition is not met after %s", time.Since(start).Round(time.Millisecond))
+		}
+
+		if time.Since(start) >= duration {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package k8t
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func constantChecker(result bool, err error) Checker {
+	return func(context.Context, *Cluster) (bool, error) {
+		return result, err
+	}
+}
+
+func Test_All(t *testing.T) {
+	ctx := context.Background()
+
+	// GIVEN: met and unmet checkers
+	met := constantChecker(true, nil)
+	unmet := constantChecker(false, nil)
+
+	// THEN: All is met only when all the checkers are met
+	if ok, _ := All(met, met)(ctx, nil); !ok {
+		t.FailNow()
+	}
+
+	if ok, _ := All(met, unmet)(ctx, nil); ok {
+		t.FailNow()
+	}
+
+	// AND: error is propagated
+	if _, err := All(met, constantChecker(false, errors.New("failed")))(ctx, nil); err == nil {
+		t.FailNow()
+	}
+}
+
+func Test_Any(t *testing.T) {
+	ctx := context.Background()
+
+	// GIVEN: met and unmet checkers
+	met := constantChecker(true, nil)
+	unmet := constantChecker(false, nil)
+
+	// THEN: Any is met when one of the checkers is met
+	if ok, _ := Any(unmet, met)(ctx, nil); !ok {
+		t.FailNow()
+	}
+
+	if ok, _ := Any(unmet, unmet)(ctx, nil); ok {
+		t.FailNow()
+	}
+}
+
+func Test_AnyWithError(t *testing.T) {
+	ctx := context.Background()
+
+	// GIVEN: first checker ends with error and second is met
+	failing := constantChecker(false, errors.New("job succeeded"))
+	met := constantChecker(true, nil)
+
+	// THEN: Any is met
+	if ok, err := Any(failing, met)(ctx, nil); !ok || err != nil {
+		t.FailNow()
+	}
+
+	// AND: the error is returned only when none is met
+	if ok, err := Any(failing, constantChecker(false, nil))(ctx, nil); ok || err == nil {
+		t.FailNow()
+	}
+
+	// AND: the error is terminal only if all the checkers are terminal
+	stuck := constantChecker(false, Terminalf("pod is in CrashLoopBackOff"))
+	if _, err := Any(stuck, constantChecker(false, nil))(ctx, nil); err == nil || errors.Is(err, ErrTerminal) {
+		t.FailNow()
+	}
+
+	if _, err := Any(stuck, stuck)(ctx, nil); !errors.Is(err, ErrTerminal) {
+		t.FailNow()
+	}
+}
+
+func Test_Not(t *testing.T) {
+	ctx := context.Background()
+
+	// THEN: Not negates the result
+	if ok, _ := Not(constantChecker(false, nil))(ctx, nil); !ok {
+		t.FailNow()
+	}
+
+	// AND: error is not negated
+	if ok, err := Not(constantChecker(false, errors.New("failed")))(ctx, nil); ok || err == nil {
+		t.FailNow()
+	}
+
+	// AND: terminal error means the negated checker is met
+	if ok, err := Not(constantChecker(false, Terminalf("pod is crashing")))(ctx, nil); !ok || err != nil {
+		t.FailNow()
+	}
+}
+
+func Test_Consistently(t *testing.T) {
+	cluster := &Cluster{}
+	opts := ConsistentlyOpts{
+		Duration: 50 * time.Millisecond,
+		Interval: 10 * time.Millisecond,
+	}
+
+	// GIVEN: checker which is met 3 times and then not
+	calls := 0
+	flapping := func(context.Context, *Cluster) (bool, error) {
+		calls++
+		return calls <= 3, nil
+	}
+
+	// WHEN: I check the condition is met consistently
+	err := cluster.Consistently(flapping, opts)
+
+	// THEN: it fails
+	if err == nil || calls != 4 {
+		t.FailNow()
+	}
+
+	// AND: checker which is always met passes
+	err = cluster.Consistently(constantChecker(true, nil), opts)
+	if err != nil {
+		t.FailNow()
+	}
+}
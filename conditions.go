@@ -0,0 +1,141 @@
+package k8t
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Optional options for HasConditionWithOpts function
+type ConditionOpts struct {
+	// namespace where is the resource. If it's not set, the cluster's
+	// testNamespace will be used
+	Namespace string
+
+	// if it's set, the condition's reason must be equal
+	Reason string
+
+	// if it's set, the condition's message must match this regular
+	// expression, e.g. 'replicas? (is|are) ready'
+	Message string
+}
+
+// check if given resource in cluster's test namespace has condition of
+// given type and status in 'status.conditions', e.g.:
+//
+//	err := cluster.WaitFor(k8t.HasCondition("cert-manager.io/v1", "Certificate", "my-cert", "Ready", "True"))
+//
+// The condition is met only when it's not stale. When the condition has
+// 'observedGeneration' (or the 'status.observedGeneration' is set), it must
+// match the 'metadata.generation', so the condition from the previous spec
+// doesn't satisfy the wait. Missing resource is not an error, the checker
+// is just not met.
+func HasCondition(apiVersion, kind, name, condType, status string) Checker {
+	return HasConditionWithOpts(apiVersion, kind, name, condType, status, ConditionOpts{})
+}
+
+// More verbose version of HasCondition() where you can match also the
+// condition's reason and message, or set namespace.
+func HasConditionWithOpts(apiVersion, kind, name, condType, status string, opts ConditionOpts) Checker {
+	var message *regexp.Regexp
+	var compileErr error
+	if opts.Message != "" {
+		message, compileErr = regexp.Compile(opts.Message)
+	}
+
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		if compileErr != nil {
+			return false, fmt.Errorf("%w: invalid message expression: %w", ErrTerminal, compileErr)
+		}
+
+		obj, err := c.GetWithOpts(apiVersion, kind, name, GetOpts{Context: ctx, Namespace: opts.Namespace})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		cond := findCondition(obj, condType)
+		switch {
+		case cond == nil:
+			return false, nil
+		case cond.isStale(obj):
+			return false, nil
+		case cond.status != status:
+			return false, nil
+		case opts.Reason != "" && cond.reason != opts.Reason:
+			return false, nil
+		case message != nil && !message.MatchString(cond.message):
+			return false, nil
+		default:
+			return true, nil
+		}
+	}
+}
+
+// single entry of 'status.conditions'
+type condition struct {
+	condType           string
+	status             string
+	reason             string
+	message            string
+	observedGeneration int64
+}
+
+// returns condition description for status message
+func (cond *condition) describe() string {
+	msg := fmt.Sprintf("%s is %s", cond.condType, cond.status)
+	if cond.reason != "" {
+		msg += ", " + cond.reason
+	}
+	if cond.message != "" {
+		msg += ": " + cond.message
+	}
+	return msg
+}
+
+// returns true if condition was computed for older generation of the
+// object. Condition's 'observedGeneration' is preferred, the status'
+// 'observedGeneration' is used when condition doesn't have any.
+func (cond *condition) isStale(obj *unstructured.Unstructured) bool {
+	observed := cond.observedGeneration
+	if observed == 0 {
+		observed, _, _ = unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	}
+
+	return observed > 0 && observed < obj.GetGeneration()
+}
+
+// find condition of given type in 'status.conditions'. It returns nil if
+// there is no such condition.
+func findCondition(obj *unstructured.Unstructured, condType string) *condition {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if fmt.Sprint(m["type"]) != condType {
+			continue
+		}
+
+		cond := &condition{condType: condType}
+		cond.status, _ = m["status"].(string)
+		cond.reason, _ = m["reason"].(string)
+		cond.message, _ = m["message"].(string)
+
+		switch generation := m["observedGeneration"].(type) {
+		case int64:
+			cond.observedGeneration = generation
+		case float64:
+			cond.observedGeneration = int64(generation)
+		}
+		return cond
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package k8t
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newConditionTestObject(generation int64, conditions ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "k8t.sn3d.com/v1",
+		"kind":       "Greeting",
+		"metadata":   map[string]interface{}{"name": "hello", "generation": generation},
+		"status":     map[string]interface{}{"conditions": conditions},
+	}}
+}
+
+func Test_findCondition(t *testing.T) {
+	// GIVEN: object with Ready condition
+	obj := newConditionTestObject(1, map[string]interface{}{
+		"type":    "Ready",
+		"status":  "False",
+		"reason":  "Pending",
+		"message": "waiting for 2 replicas",
+	})
+
+	// THEN: the condition is found with all fields
+	cond := findCondition(obj, "Ready")
+	if cond == nil || cond.status != "False" || cond.reason != "Pending" || cond.message != "waiting for 2 replicas" {
+		t.FailNow()
+	}
+
+	// AND: unknown condition is not found
+	if findCondition(obj, "Available") != nil {
+		t.FailNow()
+	}
+}
+
+func Test_conditionIsStale(t *testing.T) {
+	// GIVEN: object where condition was computed for previous generation
+	obj := newConditionTestObject(2, map[string]interface{}{
+		"type":               "Ready",
+		"status":             "True",
+		"observedGeneration": int64(1),
+	})
+
+	// THEN: the condition is stale
+	if !findCondition(obj, "Ready").isStale(obj) {
+		t.FailNow()
+	}
+
+	// WHEN: the condition is computed for current generation
+	obj = newConditionTestObject(2, map[string]interface{}{
+		"type":               "Ready",
+		"status":             "True",
+		"observedGeneration": int64(2),
+	})
+
+	// THEN: the condition is not stale
+	if findCondition(obj, "Ready").isStale(obj) {
+		t.FailNow()
+	}
+
+	// WHEN: the condition has no observedGeneration, but status has older one
+	obj = newConditionTestObject(2, map[string]interface{}{
+		"type":   "Ready",
+		"status": "True",
+	})
+	unstructured.SetNestedField(obj.Object, int64(1), "status", "observedGeneration")
+
+	// THEN: the condition is stale
+	if !findCondition(obj, "Ready").isStale(obj) {
+		t.FailNow()
+	}
+}
+
+func Test_HasConditionInvalidMessage(t *testing.T) {
+	// GIVEN: checker with invalid message expression
+	check := HasConditionWithOpts("v1", "Pod", "my-pod", "Ready", "True", ConditionOpts{Message: "("})
+
+	// THEN: checker fails without touching the cluster
+	if _, err := check(context.Background(), nil); err == nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,109 @@
+package k8t
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// field owned by another field manager, which caused apply conflict
+type FieldConflict struct {
+	// path to the field, e.g. '.spec.replicas'
+	Field string
+
+	// name of the field manager who owns the field
+	Manager string
+
+	// original message from server
+	Message string
+}
+
+// Error returned by apply when some fields are owned by another field
+// manager and ApplyOpts.Force is not set. You can get it with errors.As():
+//
+//	var conflict *k8t.ConflictError
+//	if errors.As(err, &conflict) {
+//	   owner := conflict.Owner(".spec.replicas")
+//	}
+type ConflictError struct {
+	// object which cannot be applied, e.g. 'Deployment/my-app'
+	Object string
+
+	Conflicts []FieldConflict
+
+	// original error from server
+	err error
+}
+
+func (e *ConflictError) Error() string {
+	fields := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		fields[i] = fmt.Sprintf("%s (owned by %s)", c.Field, c.Manager)
+	}
+	return fmt.Sprintf("apply conflicts on %s: %s", e.Object, strings.Join(fields, ", "))
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.err
+}
+
+// returns manager owning given conflicting field, or empty string if
+// there is no conflict for the field
+func (e *ConflictError) Owner(field string) string {
+	for _, c := range e.Conflicts {
+		if c.Field == field {
+			return c.Manager
+		}
+	}
+	return ""
+}
+
+// the message is e.g. 'conflict with "kube-controller-manager" using apps/v1'
+var conflictManagerRegexp = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+// convert apply conflict returned by server into ConflictError. Other
+// errors are returned as they are.
+func asConflictError(obj *unstructured.Unstructured, err error) error {
+	var statusErr *apierrors.StatusError
+	if !apierrors.IsConflict(err) || !errors.As(err, &statusErr) {
+		return err
+	}
+
+	details := statusErr.ErrStatus.Details
+	if details == nil {
+		return err
+	}
+
+	conflicts := make([]FieldConflict, 0)
+	for _, cause := range details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+
+		conflict := FieldConflict{
+			Field:   cause.Field,
+			Message: cause.Message,
+		}
+
+		if m := conflictManagerRegexp.FindStringSubmatch(cause.Message); m != nil {
+			conflict.Manager = m[1]
+		}
+
+		conflicts = append(conflicts, conflict)
+	}
+
+	if len(conflicts) == 0 {
+		return err
+	}
+
+	return &ConflictError{
+		Object:    objectRef(obj),
+		Conflicts: conflicts,
+		err:       err,
+	}
+}
@@ -0,0 +1,60 @@
+package k8t
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_asConflictError(t *testing.T) {
+	// GIVEN: conflict returned by server for apply
+	serverErr := &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   http.StatusConflict,
+		Reason: metav1.StatusReasonConflict,
+		Details: &metav1.StatusDetails{
+			Causes: []metav1.StatusCause{
+				{
+					Type:    metav1.CauseTypeFieldManagerConflict,
+					Message: `conflict with "kube-controller-manager" using apps/v1`,
+					Field:   ".spec.replicas",
+				},
+			},
+		},
+	}}
+
+	// WHEN: I convert the error, wrapped in the same way as apply does
+	err := fmt.Errorf("Deployment/app: %w", asConflictError(newTestObject("apps/v1", "Deployment", "app"), serverErr))
+
+	// THEN: I get the conflict error with the owner of the field
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.FailNow()
+	}
+
+	if conflict.Object != "Deployment/app" || conflict.Owner(".spec.replicas") != "kube-controller-manager" {
+		t.FailNow()
+	}
+
+	// AND: the original error is still available
+	if !apierrors.IsConflict(conflict) {
+		t.FailNow()
+	}
+}
+
+func Test_asConflictError_OtherError(t *testing.T) {
+	// GIVEN: error which is not a conflict
+	serverErr := errors.New("some error")
+
+	// WHEN: I convert the error
+	err := asConflictError(newTestObject("v1", "Pod", "pod"), serverErr)
+
+	// THEN: the error is returned as it is
+	if err != serverErr {
+		t.FailNow()
+	}
+}
@@ -2,16 +2,22 @@ package k8t
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
-	"k8s.io/client-go/discovery"
-	memory "k8s.io/client-go/discovery/cached"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/restmapper"
 )
 
 // options for DeleteWithOpts function
@@ -20,9 +26,51 @@ type DeleteOpts struct {
 	// context.Background() will be used
 	Context context.Context
 
-	// namespace where delete resource. If it's not set, the cluster's
-	// test namespace will be used. This is ignored for cluster-wide resources
+	// namespace where delete resource. If it's not set, the namespace
+	// from object's metadata or the cluster's test namespace will be used.
+	// Objects with different namespace in metadata are rejected. This is
+	// ignored for cluster-wide resources
 	Namespace string
+
+	// how the dependent objects are deleted, 'Foreground', 'Background' or
+	// 'Orphan'. If it's not set, the default policy of the resource is used.
+	PropagationPolicy metav1.DeletionPropagation
+
+	// the duration in seconds before the object should be deleted. Zero
+	// means delete immediately. If it's not set, the default grace period
+	// of the resource is used.
+	GracePeriodSeconds *int64
+
+	// if it's set, the function blocks until all deleted objects are
+	// really gone, not only terminating
+	Wait bool
+
+	// maximum duration for waiting. If it's not set, the default
+	// 2 minutes will be used. The timeout is shared by all deleted objects.
+	// When finalizers are removed, the timeout starts after the removal.
+	Timeout time.Duration
+
+	// if it's set, objects which don't exist are not reported as error
+	IgnoreNotFound bool
+
+	// opt-in for objects stuck in Terminating. If it's set, the objects
+	// which are still terminating after this duration have their finalizers
+	// removed. It implies Wait.
+	RemoveFinalizersAfter time.Duration
+}
+
+// returns options for delete request
+func (opts DeleteOpts) deleteOptions() metav1.DeleteOptions {
+	deleteOpts := metav1.DeleteOptions{
+		GracePeriodSeconds: opts.GracePeriodSeconds,
+	}
+
+	if opts.PropagationPolicy != "" {
+		policy := opts.PropagationPolicy
+		deleteOpts.PropagationPolicy = &policy
+	}
+
+	return deleteOpts
 }
 
 // Delete resource in given file
@@ -35,70 +83,281 @@ func (c *Cluster) DeleteFile(path string) error {
 
 }
 
-// Delete resource of given YAML. The resource must be located in
-// cluster's default test namespace or it must be cluster-wide resource. If
-// you want to delete resource in another namespace, you should use more
-// verbose DeleteWithOpts.
+// Delete all resources from manifests ('.yaml', '.yml' and '.json' files)
+// in given directory and its subdirectories.
+func (c *Cluster) DeleteDir(path string) error {
+	return c.DeleteFS(os.DirFS(path), ".")
+}
+
+// Delete all resources from manifests matching glob pattern in given
+// filesystem. It's counterpart of ApplyFS().
+func (c *Cluster) DeleteFS(fsys fs.FS, glob string) error {
+	return c.DeleteFSWithOpts(fsys, glob, DeleteOpts{})
+}
+
+// More verbose version of DeleteFS() function that allow you pass the
+// context or change the namespace etc.
+func (c *Cluster) DeleteFSWithOpts(fsys fs.FS, glob string, opts DeleteOpts) error {
+	objs, err := loadManifestsFromFS(fsys, glob)
+	if err != nil {
+		return err
+	}
+
+	return c.deleteObjects(objs, opts)
+}
+
+// Render manifest template with given data and delete resources in it.
+// It's counterpart of ApplyTemplate().
+func (c *Cluster) DeleteTemplate(tmpl string, data any) error {
+	return c.DeleteTemplateWithOpts(tmpl, data, DeleteOpts{})
+}
+
+// Render manifest template on given path with given data and delete
+// resources in it. It's counterpart of ApplyTemplateFile().
+func (c *Cluster) DeleteTemplateFile(path string, data any) error {
+	tmpl, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	yml, err := renderTemplate(filepath.Base(path), string(tmpl), data)
+	if err != nil {
+		return err
+	}
+
+	return c.Delete(yml)
+}
+
+// More verbose version of DeleteTemplate() function that allow you pass
+// the context or change the namespace etc.
+func (c *Cluster) DeleteTemplateWithOpts(tmpl string, data any, opts DeleteOpts) error {
+	yml, err := renderTemplate("manifest", tmpl, data)
+	if err != nil {
+		return err
+	}
+
+	return c.DeleteWithOpts(yml, opts)
+}
+
+// Delete resource of given YAML. Resources without namespace in metadata
+// are deleted in cluster's default test namespace, resources with explicit
+// namespace are deleted in their namespace. If you want to delete resources
+// in another namespace, you should use more verbose DeleteWithOpts.
 func (c *Cluster) Delete(yml string) error {
 	return c.DeleteWithOpts(yml, DeleteOpts{})
 }
 
 // More verbose version of Delete() function that allow you pass the context
 // or change the namespace etc.
+//
+// The YAML can contain multiple documents separated by '---', or 'kind: List'
+// objects. All the objects are deleted, even if some of them fail. The
+// returned error contains all the objects which failed.
+//
+// Objects are deleted in reversed order than Apply() is using, so custom
+// resources and workloads are deleted before CRDs and namespaces.
 func (c *Cluster) DeleteWithOpts(yml string, opts DeleteOpts) error {
+	// Decode YAML manifest into unstructured.Unstructured objects
+	objs, err := DecodeManifest(yml)
+	if err != nil {
+		return err
+	}
+
+	return c.deleteObjects(objs, opts)
+}
+
+// delete all given objects
+func (c *Cluster) deleteObjects(objs []*unstructured.Unstructured, opts DeleteOpts) error {
 
 	ctx := opts.Context
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	namespace := opts.Namespace
-	if namespace == "" {
-		namespace = c.testNamespace
+	// Prepare the dynamic client
+	dyn, err := dynamic.NewForConfig(c.restConfig)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, 0)
+	deleted := make([]deletedObject, 0, len(objs))
+	for _, obj := range sortForDelete(objs) {
+		d, err := deleteObject(ctx, dyn, c.restMapper, obj, opts.Namespace, c.testNamespace, opts.deleteOptions())
+		if apierrors.IsNotFound(err) && opts.IgnoreNotFound {
+			continue
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", objectRef(obj), err))
+			continue
+		}
+
+		deleted = append(deleted, d)
 	}
 
-	// Decode YAML manifest into unstructured.Unstructured
-	decUnstructured := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
-	obj := &unstructured.Unstructured{}
-	_, gvk, err := decUnstructured.Decode([]byte(yml), nil, obj)
+	errs = append(errs, c.waitForDeletions(ctx, deleted, opts)...)
+	return errors.Join(errs...)
+}
+
+// object which was deleted, together with REST interface and UID, so we
+// can check if it's gone
+type deletedObject struct {
+	obj *unstructured.Unstructured
+	dr  dynamic.ResourceInterface
+	uid types.UID
+}
+
+// wait until all deleted objects are gone, if it's requested by opts. All
+// the objects share the same deadline. Function returns errors for objects
+// which are not gone.
+func (c *Cluster) waitForDeletions(ctx context.Context, deleted []deletedObject, opts DeleteOpts) []error {
+	if !opts.Wait && opts.RemoveFinalizersAfter == 0 {
+		return nil
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 120 * time.Second
+	}
+
+	start := time.Now()
+	var removeFinalizersAt time.Time
+	if opts.RemoveFinalizersAfter > 0 {
+		removeFinalizersAt = start.Add(opts.RemoveFinalizersAfter)
+	}
+	deadline := start.Add(opts.RemoveFinalizersAfter + timeout)
+
+	errs := make([]error, 0)
+	for _, d := range deleted {
+		err := c.waitForDeletion(ctx, d, removeFinalizersAt, deadline)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", objectRef(d.obj), err))
+		}
+	}
+	return errs
+}
+
+// wait until deleted object is gone. If removeFinalizersAt is set, the
+// object's finalizers are removed when it's still terminating at this time
+// and then we're waiting again until the deadline.
+func (c *Cluster) waitForDeletion(ctx context.Context, d deletedObject, removeFinalizersAt, deadline time.Time) error {
+	if !removeFinalizersAt.IsZero() {
+		err := c.waitUntilGone(ctx, d, removeFinalizersAt)
+		if err == nil {
+			return nil
+		}
+
+		// only objects stuck in Terminating, not e.g. cancelled waiting
+		if !wait.Interrupted(err) || ctx.Err() != nil {
+			return fmt.Errorf("object is not deleted: %w", err)
+		}
+
+		err = c.removeFinalizers(ctx, d)
+		if err != nil {
+			return fmt.Errorf("cannot remove finalizers: %w", err)
+		}
+	}
+
+	err := c.waitUntilGone(ctx, d, deadline)
 	if err != nil {
-		return err
+		return fmt.Errorf("object is not deleted: %w", err)
 	}
 
-	// Prepare a RESTMapper and find GVR
-	dc, err := discovery.NewDiscoveryClientForConfig(c.restConfig)
+	return nil
+}
+
+// remove all finalizers of the deleted object. The patch contains the UID,
+// so the object recreated with the same name is rejected by server and
+// left alone.
+func (c *Cluster) removeFinalizers(ctx context.Context, d deletedObject) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"uid":        d.uid,
+			"finalizers": nil,
+		},
+	})
 	if err != nil {
 		return err
 	}
 
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
-	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
-	if err != nil {
+	_, err = d.dr.Patch(ctx, d.obj.GetName(), types.MergePatchType, patch, metav1.PatchOptions{
+		FieldManager: "k8t",
+	})
+	if err == nil || apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	// the patch is rejected also when the object was recreated
+	if gone, goneErr := objectIsGone(d)(ctx, c); goneErr == nil && gone {
+		return nil
+	}
+	return err
+}
+
+// wait until deleted object is gone, or the deadline is reached
+func (c *Cluster) waitUntilGone(ctx context.Context, d deletedObject, deadline time.Time) error {
+	remaining := time.Until(deadline)
+	if remaining > 0 {
+		return c.WaitForWithOpts(objectIsGone(d), WaitForOpts{Context: ctx, Timeout: remaining})
+	}
+
+	// deadline was spent by other objects, we check only once
+	gone, err := objectIsGone(d)(ctx, c)
+	switch {
+	case err != nil:
 		return err
+	case !gone:
+		return context.DeadlineExceeded
+	default:
+		return nil
 	}
+}
 
-	// Prepare the dynamic client
-	dyn, err := dynamic.NewForConfig(c.restConfig)
+// check if deleted object doesn't exist anymore. The object recreated with
+// the same name (e.g. by controller) has different UID and the deleted one
+// is considered gone.
+func objectIsGone(d deletedObject) Checker {
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		obj, err := d.dr.Get(ctx, d.obj.GetName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return d.uid != "" && obj.GetUID() != d.uid, nil
+	}
+}
+
+// delete single object in namespace. It returns the deleted object with
+// REST interface and UID, so the caller can check when the object is gone.
+// The object is deleted with UID precondition, so we never delete another
+// object recreated with the same name.
+func deleteObject(ctx context.Context, dyn dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured, namespace, defaultNamespace string, deleteOpts metav1.DeleteOptions) (deletedObject, error) {
+	mapping, namespace, err := objectMapping(mapper, obj, namespace, defaultNamespace)
 	if err != nil {
-		return err
+		return deletedObject{}, err
 	}
 
 	// Obtain REST interface for the GVR
-	var dr dynamic.ResourceInterface
-	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
-		// namespaced resources are placed into test Namespace
-		dr = dyn.Resource(mapping.Resource).Namespace(c.testNamespace)
-	} else {
-		// for cluster-wide resources
-		dr = dyn.Resource(mapping.Resource)
-	}
+	dr := resourceInterface(dyn, mapping, namespace)
 
-	// Create or Update the object with server-side-apply
-	err = dr.Delete(ctx, obj.GetName(), metav1.DeleteOptions{})
+	// manifests don't have UID, we need the live object
+	uid := obj.GetUID()
+	if uid == "" {
+		live, err := dr.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return deletedObject{}, err
+		}
+		uid = live.GetUID()
+	}
 
+	deleteOpts.Preconditions = &metav1.Preconditions{UID: &uid}
+	err = dr.Delete(ctx, obj.GetName(), deleteOpts)
 	if err != nil {
-		return err
-	} else {
-		return nil
+		return deletedObject{}, err
 	}
+
+	return deletedObject{obj: obj, dr: dr, uid: uid}, nil
 }
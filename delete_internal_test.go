@@ -0,0 +1,95 @@
+package k8t
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func Test_objectIsGone(t *testing.T) {
+	ctx := context.Background()
+
+	// GIVEN: deleted config map which was recreated with the same name
+	recreated := newTestConfigMap("my-config", "2")
+	recreated.SetUID("new-uid")
+
+	dyn := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), recreated)
+	d := deletedObject{
+		obj: newTestConfigMap("my-config", "1"),
+		dr:  dyn.Resource(configMapsGVR).Namespace("test"),
+		uid: "old-uid",
+	}
+
+	// THEN: the deleted object is gone
+	if gone, err := objectIsGone(d)(ctx, nil); !gone || err != nil {
+		t.FailNow()
+	}
+
+	// AND: the object with the same UID is not gone
+	d.uid = "new-uid"
+	if gone, err := objectIsGone(d)(ctx, nil); gone || err != nil {
+		t.FailNow()
+	}
+}
+
+func Test_waitForDeletionsSharedDeadline(t *testing.T) {
+	// GIVEN: three objects stuck in terminating
+	objs := make([]runtime.Object, 0)
+	deleted := make([]deletedObject, 0)
+	for _, name := range []string{"first", "second", "third"} {
+		obj := newTestConfigMap(name, "1")
+		obj.SetUID(types.UID("uid-" + name))
+		objs = append(objs, obj)
+		deleted = append(deleted, deletedObject{obj: obj, uid: obj.GetUID()})
+	}
+	dyn := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objs...)
+	for i := range deleted {
+		deleted[i].dr = dyn.Resource(configMapsGVR).Namespace("test")
+	}
+
+	// WHEN: I wait for them with short timeout
+	start := time.Now()
+	errs := (&Cluster{}).waitForDeletions(context.Background(), deleted, DeleteOpts{Wait: true, Timeout: 200 * time.Millisecond})
+
+	// THEN: all of them are reported
+	if len(errs) != 3 {
+		t.FailNow()
+	}
+
+	// AND: the timeout is shared, not multiplied by number of objects
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("waiting took %s", time.Since(start))
+	}
+}
+
+func Test_removeFinalizersWithUID(t *testing.T) {
+	// GIVEN: deleted object stuck on finalizer
+	obj := newTestConfigMap("stuck", "1")
+	obj.SetUID("stuck-uid")
+	obj.SetFinalizers([]string{"example.com/cleanup"})
+
+	dyn := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), obj)
+	var patch []byte
+	dyn.PrependReactor("patch", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch = action.(k8stesting.PatchAction).GetPatch()
+		return false, nil, nil
+	})
+
+	d := deletedObject{obj: obj, dr: dyn.Resource(configMapsGVR).Namespace("test"), uid: obj.GetUID()}
+
+	// WHEN: I remove the finalizers
+	err := (&Cluster{}).removeFinalizers(context.Background(), d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: the patch is guarded by UID
+	if string(patch) != `{"metadata":{"finalizers":null,"uid":"stuck-uid"}}` {
+		t.Fatal(string(patch))
+	}
+}
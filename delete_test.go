@@ -42,3 +42,48 @@ func Test_Delete(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func Test_DeleteWait(t *testing.T) {
+
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster with running pod
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	err = cluster.ApplyFile("testdata/test-agent.yaml")
+	if err != nil {
+		t.FailNow()
+	}
+
+	data, err := os.ReadFile("testdata/test-agent.yaml")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: we delete the pod and wait
+	gracePeriod := int64(0)
+	err = cluster.DeleteWithOpts(string(data), k8t.DeleteOpts{
+		GracePeriodSeconds: &gracePeriod,
+		Wait:               true,
+	})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: the pod is gone immediately
+	_, err = cluster.Get("v1", "Pod", "test-agent")
+	if err == nil {
+		t.FailNow()
+	}
+
+	// AND: another delete is fine when we ignore not found objects
+	err = cluster.DeleteWithOpts(string(data), k8t.DeleteOpts{IgnoreNotFound: true})
+	if err != nil {
+		t.FailNow()
+	}
+}
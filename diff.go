@@ -0,0 +1,209 @@
+package k8t
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// single field which differs between live object and object after apply
+type FieldChange struct {
+	// path to the field, e.g. 'spec.template.spec.containers[0].image'
+	Path string
+
+	// value of the field in live object, nil if the field is added
+	Old any
+
+	// value of the field after apply, nil if the field is removed
+	New any
+}
+
+func (fc FieldChange) String() string {
+	switch {
+	case fc.Old == nil:
+		return fmt.Sprintf("+ %s: %v", fc.Path, fc.New)
+	case fc.New == nil:
+		return fmt.Sprintf("- %s: %v", fc.Path, fc.Old)
+	default:
+		return fmt.Sprintf("~ %s: %v -> %v", fc.Path, fc.Old, fc.New)
+	}
+}
+
+// difference of one object between cluster and manifest
+type ObjectDiff struct {
+	// the object as it would look like after apply (dry-run result)
+	Object *unstructured.Unstructured
+
+	// the object as it's in cluster now, nil if object doesn't exist
+	Live *unstructured.Unstructured
+
+	// what apply would do with the object
+	Outcome ApplyOutcome
+
+	// changed fields. It's empty for objects which would be created
+	Changes []FieldChange
+}
+
+// result of Diff() function, one entry for each object in manifest
+type DiffResult struct {
+	Objects []ObjectDiff
+}
+
+// returns true if apply would create or change any object
+func (d DiffResult) HasChanges() bool {
+	for _, o := range d.Objects {
+		if o.Outcome != Unchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// print diff in human readable form:
+//
+//	deployment/my-app configured
+//	  ~ spec.replicas: 1 -> 3
+//	configmap/my-config created
+func (d DiffResult) String() string {
+	var sb strings.Builder
+	for _, o := range d.Objects {
+		fmt.Fprintf(&sb, "%s/%s %s\n", strings.ToLower(o.Object.GetKind()), o.Object.GetName(), o.Outcome)
+		for _, change := range o.Changes {
+			fmt.Fprintf(&sb, "  %s\n", change)
+		}
+	}
+	return sb.String()
+}
+
+// Compare manifest with live objects in cluster's test namespace. See
+// DiffWithOpts() for more details.
+func (c *Cluster) Diff(yml string) (DiffResult, error) {
+	return c.DiffWithOpts(yml, ApplyOpts{})
+}
+
+// Preview what apply of given manifest would change. All objects are applied
+// with server-side dry-run and the result is compared with the current live
+// objects. The 'managedFields', 'resourceVersion', 'generation' and
+// 'status' are ignored, because they're maintained by the server.
+//
+// The DryRun option is always set, other apply options are respected.
+func (c *Cluster) DiffWithOpts(yml string, opts ApplyOpts) (DiffResult, error) {
+	opts.DryRun = true
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	objs, err := DecodeManifest(yml)
+	if err != nil {
+		return DiffResult{}, err
+	}
+
+	dyn, err := dynamic.NewForConfig(c.restConfig)
+	if err != nil {
+		return DiffResult{}, err
+	}
+
+	result := DiffResult{Objects: make([]ObjectDiff, 0, len(objs))}
+	errs := make([]error, 0)
+	for _, obj := range sortForApply(objs) {
+		existing, dryRun, err := applyObject(ctx, dyn, c.restMapper, obj, opts.Namespace, c.testNamespace, opts.patchOptions())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", objectRef(obj), err))
+			continue
+		}
+
+		objDiff := ObjectDiff{
+			Object:  dryRun,
+			Live:    existing,
+			Outcome: Created,
+		}
+
+		if existing != nil {
+			objDiff.Changes = diffObjects(existing, dryRun)
+			objDiff.Outcome = Unchanged
+			if len(objDiff.Changes) > 0 {
+				objDiff.Outcome = Configured
+			}
+		}
+
+		result.Objects = append(result.Objects, objDiff)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// compare two objects and returns all changed fields. Fields maintained
+// by server are ignored.
+func diffObjects(old, new *unstructured.Unstructured) []FieldChange {
+	changes := make([]FieldChange, 0)
+	diffValues("", cleanForDiff(old), cleanForDiff(new), &changes)
+	return changes
+}
+
+// returns copy of object's content without the fields maintained by server
+func cleanForDiff(obj *unstructured.Unstructured) map[string]interface{} {
+	clean := obj.DeepCopy()
+	unstructured.RemoveNestedField(clean.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(clean.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(clean.Object, "metadata", "generation")
+	unstructured.RemoveNestedField(clean.Object, "status")
+	return clean.Object
+}
+
+// recursively compare values and collect changes
+func diffValues(path string, old, new interface{}, changes *[]FieldChange) {
+	oldMap, oldIsMap := old.(map[string]interface{})
+	newMap, newIsMap := new.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		keys := make([]string, 0, len(oldMap)+len(newMap))
+		for k := range oldMap {
+			keys = append(keys, k)
+		}
+		for k := range newMap {
+			if _, ok := oldMap[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			diffValues(joinPath(path, k), oldMap[k], newMap[k], changes)
+		}
+		return
+	}
+
+	oldSlice, oldIsSlice := old.([]interface{})
+	newSlice, newIsSlice := new.([]interface{})
+	if oldIsSlice && newIsSlice {
+		for i := 0; i < len(oldSlice) || i < len(newSlice); i++ {
+			var o, n interface{}
+			if i < len(oldSlice) {
+				o = oldSlice[i]
+			}
+			if i < len(newSlice) {
+				n = newSlice[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), o, n, changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		*changes = append(*changes, FieldChange{Path: path, Old: old, New: new})
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,71 @@
+package k8t
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_diffObjects(t *testing.T) {
+	// GIVEN: live deployment
+	live := newTestObject("apps/v1", "Deployment", "app")
+	live.SetResourceVersion("1")
+	_ = unstructured.SetNestedField(live.Object, int64(1), "spec", "replicas")
+	_ = unstructured.SetNestedSlice(live.Object, []interface{}{
+		map[string]interface{}{"name": "app", "image": "app:1"},
+	}, "spec", "template", "spec", "containers")
+	_ = unstructured.SetNestedField(live.Object, "old", "metadata", "labels", "removed")
+	_ = unstructured.SetNestedField(live.Object, int64(1), "status", "readyReplicas")
+
+	// AND: the same deployment after apply
+	applied := live.DeepCopy()
+	applied.SetResourceVersion("2")
+	_ = unstructured.SetNestedField(applied.Object, int64(3), "spec", "replicas")
+	_ = unstructured.SetNestedSlice(applied.Object, []interface{}{
+		map[string]interface{}{"name": "app", "image": "app:2"},
+	}, "spec", "template", "spec", "containers")
+	unstructured.RemoveNestedField(applied.Object, "metadata", "labels", "removed")
+	_ = unstructured.SetNestedField(applied.Object, "new", "metadata", "labels", "added")
+	_ = unstructured.SetNestedField(applied.Object, int64(3), "status", "readyReplicas")
+
+	// WHEN: I compare them
+	changes := diffObjects(live, applied)
+
+	// THEN: I get changed fields, resourceVersion and status are ignored
+	expected := []string{
+		"+ metadata.labels.added: new",
+		"- metadata.labels.removed: old",
+		"~ spec.replicas: 1 -> 3",
+		"~ spec.template.spec.containers[0].image: app:1 -> app:2",
+	}
+
+	if len(changes) != len(expected) {
+		t.FailNow()
+	}
+
+	for i := range expected {
+		if changes[i].String() != expected[i] {
+			t.FailNow()
+		}
+	}
+}
+
+func Test_DiffResult(t *testing.T) {
+	// GIVEN: diff with one created and one unchanged object
+	diff := DiffResult{
+		Objects: []ObjectDiff{
+			{Object: newTestObject("v1", "ConfigMap", "config"), Outcome: Created},
+			{Object: newTestObject("v1", "Service", "svc"), Outcome: Unchanged},
+		},
+	}
+
+	// THEN: diff has changes
+	if !diff.HasChanges() {
+		t.FailNow()
+	}
+
+	// AND: it's printable
+	if diff.String() != "configmap/config created\nservice/svc unchanged\n" {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,302 @@
+package k8t
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Optional options for ExpectWithOpts function
+type ExpectOpts struct {
+	// namespace where is the resource. If it's not set, the cluster's
+	// testNamespace will be used
+	Namespace string
+}
+
+// check if given resource in cluster's test namespace satisfies the
+// expression. The expression is a CEL expression, where the object is
+// available as 'self':
+//
+//	err := cluster.WaitFor(k8t.Expect("apps/v1", "Deployment", "my-app", "self.status.readyReplicas == self.spec.replicas"))
+//
+// Or it can be JSONPath equality in the same form as 'kubectl wait' is
+// using. The 'jsonpath=' prefix is optional:
+//
+//	err := cluster.WaitFor(k8t.Expect("v1", "Pod", "my-pod", "{.status.phase}=Running"))
+//
+// The expression is compiled once and evaluated on every invocation of the
+// checker. Missing fields under status (e.g. status which is not populated
+// yet) mean the expression is not met. Other missing fields are probably
+// typos, they're reported as error and included in the timeout error of
+// WaitFor(). Invalid expression is reported as error wrapping ErrTerminal,
+// so the waiting ends immediately.
+func Expect(apiVersion, kind, name, expr string) Checker {
+	return ExpectWithOpts(apiVersion, kind, name, expr, ExpectOpts{})
+}
+
+// More verbose version of Expect() where you can set namespace.
+func ExpectWithOpts(apiVersion, kind, name, expr string, opts ExpectOpts) Checker {
+	eval, compileErr := compileExpression(expr)
+
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		if compileErr != nil {
+			return false, fmt.Errorf("%w: %w", ErrTerminal, compileErr)
+		}
+
+		obj, err := c.GetWithOpts(apiVersion, kind, name, GetOpts{Context: ctx, Namespace: opts.Namespace})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		return eval(obj)
+	}
+}
+
+// compiled expression which can be evaluated on object
+type expression func(*unstructured.Unstructured) (bool, error)
+
+// compile expression into function. Expressions starting with '{' or
+// 'jsonpath=' are JSONPath equalities, everything else is CEL.
+func compileExpression(expr string) (expression, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "jsonpath=") || strings.HasPrefix(expr, "{") {
+		return compileJSONPathExpression(strings.TrimPrefix(expr, "jsonpath="))
+	}
+	return compileCELExpression(expr)
+}
+
+// compile CEL expression with the object as 'self' variable. The expression
+// must evaluate to bool.
+func compileCELExpression(expr string) (expression, error) {
+	env, err := cel.NewEnv(cel.Variable("self", cel.DynType))
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression '%s': %w", expr, issues.Err())
+	}
+
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression '%s' must return bool, not %s", expr, ast.OutputType())
+	}
+
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression '%s': %w", expr, err)
+	}
+
+	paths := make([][]string, 0)
+	collectSelfPaths(ast.Expr(), &paths)
+
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		out, _, err := prg.Eval(map[string]interface{}{"self": obj.Object})
+		if err != nil && strings.Contains(err.Error(), "no such key") {
+			return false, missingFieldError(expr, obj, paths, err)
+		}
+		if err != nil {
+			return false, fmt.Errorf("expression '%s' on %s: %w", expr, objectRef(obj), err)
+		}
+
+		result, ok := out.Value().(bool)
+		if !ok {
+			return false, fmt.Errorf("expression '%s' on %s returned %v, not bool", expr, objectRef(obj), out.Value())
+		}
+		return result, nil
+	}, nil
+}
+
+// Missing field under 'status' means the status is not populated yet and
+// the expression is not met (nil error). Missing field anywhere else is
+// probably a typo, e.g. 'self.spec.replica', and it's reported as error.
+func missingFieldError(expr string, obj *unstructured.Unstructured, paths [][]string, evalErr error) error {
+	statusMissing := false
+	for _, path := range paths {
+		depth, missing := findMissingField(obj.Object, path)
+		if !missing {
+			continue
+		}
+
+		if path[0] != "status" {
+			return fmt.Errorf("expression '%s' on %s: field '%s' doesn't exist", expr, objectRef(obj), strings.Join(path[:depth+1], "."))
+		}
+		statusMissing = true
+	}
+
+	if statusMissing {
+		return nil
+	}
+	return fmt.Errorf("expression '%s' on %s: %w", expr, objectRef(obj), evalErr)
+}
+
+// returns index of the first missing field in the path, if some is missing.
+// Path which goes through non-object values (e.g. lists) is not judged.
+func findMissingField(obj map[string]interface{}, path []string) (int, bool) {
+	current := obj
+	for i, field := range path {
+		value, ok := current[field]
+		if !ok {
+			return i, true
+		}
+
+		next, ok := value.(map[string]interface{})
+		if !ok {
+			return 0, false
+		}
+		current = next
+	}
+	return 0, false
+}
+
+// collect all field paths selected from 'self' in the expression, e.g.
+// 'self.status.readyReplicas' is ["status", "readyReplicas"]. The has()
+// macros are skipped, they don't fail on missing fields.
+func collectSelfPaths(e *exprpb.Expr, paths *[][]string) {
+	if e == nil {
+		return
+	}
+
+	switch k := e.ExprKind.(type) {
+	case *exprpb.Expr_SelectExpr:
+		if path, ok := selfPath(e); ok {
+			if !k.SelectExpr.TestOnly {
+				*paths = append(*paths, path)
+			}
+			return
+		}
+		collectSelfPaths(k.SelectExpr.Operand, paths)
+
+	case *exprpb.Expr_CallExpr:
+		collectSelfPaths(k.CallExpr.Target, paths)
+		for _, arg := range k.CallExpr.Args {
+			collectSelfPaths(arg, paths)
+		}
+
+	case *exprpb.Expr_ListExpr:
+		for _, elem := range k.ListExpr.Elements {
+			collectSelfPaths(elem, paths)
+		}
+
+	case *exprpb.Expr_StructExpr:
+		for _, entry := range k.StructExpr.Entries {
+			collectSelfPaths(entry.GetMapKey(), paths)
+			collectSelfPaths(entry.GetValue(), paths)
+		}
+
+	case *exprpb.Expr_ComprehensionExpr:
+		c := k.ComprehensionExpr
+		collectSelfPaths(c.IterRange, paths)
+		collectSelfPaths(c.AccuInit, paths)
+		collectSelfPaths(c.LoopCondition, paths)
+		collectSelfPaths(c.LoopStep, paths)
+		collectSelfPaths(c.Result, paths)
+	}
+}
+
+// returns field path if the expression is chain of selects on 'self'
+func selfPath(e *exprpb.Expr) ([]string, bool) {
+	switch k := e.ExprKind.(type) {
+	case *exprpb.Expr_IdentExpr:
+		return nil, k.IdentExpr.Name == "self"
+	case *exprpb.Expr_SelectExpr:
+		path, ok := selfPath(k.SelectExpr.Operand)
+		if !ok {
+			return nil, false
+		}
+		return append(path, k.SelectExpr.Field), true
+	default:
+		return nil, false
+	}
+}
+
+// compile JSONPath equality like '{.status.phase}=Running'. If path matches
+// multiple values, all of them must be equal to expected value. Missing
+// path under status is not met, other missing paths are reported as error.
+func compileJSONPathExpression(expr string) (expression, error) {
+	path, expected, err := splitJSONPathExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	jp, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// parsed JSONPath is not safe for concurrent use, and the checker can
+	// be shared by parallel tests
+	var mu sync.Mutex
+	statusPath := isStatusPath(path)
+
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		mu.Lock()
+		value, err := queryParsed(jp, obj, path)
+		mu.Unlock()
+		if err != nil && statusPath {
+			// status is not populated yet
+			return false, nil
+		}
+		if err != nil {
+			// missing field outside of status is probably a typo
+			return false, fmt.Errorf("expression '%s': %w", expr, err)
+		}
+
+		values, ok := value.([]interface{})
+		if !ok {
+			values = []interface{}{value}
+		}
+
+		for _, v := range values {
+			if v == nil || reflect.TypeOf(v).Kind() == reflect.Map || fmt.Sprint(v) != expected {
+				return false, nil
+			}
+		}
+		return true, nil
+	}, nil
+}
+
+// returns true if JSONPath like '{.status.phase}' points into object's
+// status
+func isStatusPath(path string) bool {
+	path = strings.TrimPrefix(relaxedJSONPath(path), "{")
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	return strings.HasPrefix(path, "status.") || strings.HasPrefix(path, "status[") || path == "status}"
+}
+
+// split '{.status.phase}=Running' into path and expected value. The path
+// can contain '=' in filters, so we're looking for closing brace first.
+func splitJSONPathExpression(expr string) (string, string, error) {
+	depth := 0
+	for i, r := range expr {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth > 0 {
+				continue
+			}
+
+			// both '=' and '==' are accepted
+			value := strings.TrimSpace(expr[i+1:])
+			if !strings.HasPrefix(value, "=") {
+				return "", "", fmt.Errorf("invalid expression '%s': expected '{path}=value'", expr)
+			}
+			value = strings.TrimPrefix(strings.TrimPrefix(value, "="), "=")
+			return expr[:i+1], strings.Trim(strings.TrimSpace(value), `"'`), nil
+		}
+	}
+
+	return "", "", fmt.Errorf("invalid expression '%s': missing closing brace", expr)
+}
@@ -0,0 +1,152 @@
+package k8t
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newExpectTestDeployment(readyReplicas int64) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "my-app"},
+		"spec":       map[string]interface{}{"replicas": int64(3)},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "True"},
+			},
+		},
+	}}
+
+	if readyReplicas > 0 {
+		unstructured.SetNestedField(obj.Object, readyReplicas, "status", "readyReplicas")
+	}
+	return obj
+}
+
+func Test_CELExpression(t *testing.T) {
+	// GIVEN: compiled CEL expression
+	eval, err := compileExpression("self.status.readyReplicas == self.spec.replicas")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: it's not met when the field is missing
+	if ok, err := eval(newExpectTestDeployment(0)); ok || err != nil {
+		t.FailNow()
+	}
+
+	// AND: it's not met when only some replicas are ready
+	if ok, err := eval(newExpectTestDeployment(1)); ok || err != nil {
+		t.FailNow()
+	}
+
+	// AND: it's met when all replicas are ready
+	if ok, err := eval(newExpectTestDeployment(3)); !ok || err != nil {
+		t.FailNow()
+	}
+}
+
+func Test_CELExpressionMissingField(t *testing.T) {
+	// GIVEN: expression with typo in spec field
+	eval, err := compileExpression("self.status.readyReplicas == self.spec.replica")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: missing field is reported as error
+	if ok, err := eval(newExpectTestDeployment(3)); ok || err == nil || !strings.Contains(err.Error(), "'spec.replica'") {
+		t.FailNow()
+	}
+
+	// GIVEN: expression with has() guard
+	eval, err = compileExpression("has(self.spec.paused) || self.status.readyReplicas > 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: missing field in has() is not an error
+	if ok, err := eval(newExpectTestDeployment(1)); !ok || err != nil {
+		t.FailNow()
+	}
+
+	// AND: missing status field is not met
+	if ok, err := eval(newExpectTestDeployment(0)); ok || err != nil {
+		t.FailNow()
+	}
+}
+
+func Test_JSONPathExpression(t *testing.T) {
+	// GIVEN: JSONPath expression with filter
+	eval, err := compileExpression(`jsonpath={.status.conditions[?(@.type=="Available")].status}=True`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: it's met
+	if ok, err := eval(newExpectTestDeployment(3)); !ok || err != nil {
+		t.FailNow()
+	}
+
+	// GIVEN: JSONPath expression for number
+	eval, err = compileExpression("{.status.readyReplicas} == 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: it's met only when the value is equal
+	if ok, _ := eval(newExpectTestDeployment(3)); !ok {
+		t.FailNow()
+	}
+
+	if ok, _ := eval(newExpectTestDeployment(1)); ok {
+		t.FailNow()
+	}
+
+	// AND: missing field is not met
+	if ok, err := eval(newExpectTestDeployment(0)); ok || err != nil {
+		t.FailNow()
+	}
+}
+
+func Test_JSONPathExpressionMissingField(t *testing.T) {
+	// GIVEN: JSONPath expression with typo outside of status
+	eval, err := compileExpression("{.spec.replica}=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: missing field is reported as error
+	if ok, err := eval(newExpectTestDeployment(3)); ok || err == nil {
+		t.FailNow()
+	}
+
+	// GIVEN: JSONPath expression with missing field in status
+	eval, err = compileExpression("{.status.phsae}=Running")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: it's not met
+	if ok, err := eval(newExpectTestDeployment(3)); ok || err != nil {
+		t.FailNow()
+	}
+}
+
+func Test_InvalidExpression(t *testing.T) {
+	// THEN: invalid expressions are reported when compiled
+	invalid := []string{
+		"self.status.readyReplicas ==",
+		"self.spec.replicas + 1",
+		"{.status.phase",
+		"{.status.phase}",
+	}
+
+	for _, expr := range invalid {
+		if _, err := compileExpression(expr); err == nil {
+			t.Errorf("expression '%s' should be invalid", expr)
+		}
+	}
+}
@@ -5,8 +5,6 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/dynamic"
 )
 
 // Optional options for GetWithOpts function
@@ -40,26 +38,12 @@ func (c *Cluster) GetWithOpts(apiVersion, kind, name string, opts GetOpts) (*uns
 		namespace = c.testNamespace
 	}
 
-	// we need to parse and convert apiVersion and Kind into
-	// GroupVersionResource
-	gvk := schema.FromAPIVersionAndKind(apiVersion, kind)
-
-	gk := schema.GroupKind{
-		Group: gvk.Group,
-		Kind:  gvk.Kind,
-	}
-
-	rm, err := c.restMapper.RESTMapping(gk, gvk.Version)
-	if err != nil {
-		return nil, err
-	}
-
-	d, err := dynamic.NewForConfig(c.restConfig)
+	dr, err := c.resourceFor(apiVersion, kind, namespace)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := d.Resource(rm.Resource).Namespace(c.testNamespace).Get(ctx, name, metav1.GetOptions{})
+	res, err := dr.Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
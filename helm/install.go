@@ -8,6 +8,7 @@ import (
 
 	"github.com/sn3d/k8t"
 	"helm.sh/helm/v3/pkg/action"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 type InstallOpts struct {
@@ -35,11 +36,17 @@ type InstallOpts struct {
 // Install helm chart to cluster from given directory, with provided
 // values. This is simple less-verbose version of InstallWithOpts
 func Install(cluster *k8t.Cluster, dir string, values Value) error {
-	return InstallWithOpts(cluster, dir, values, InstallOpts{})
+	_, err := InstallWithOpts(cluster, dir, values, InstallOpts{})
+	return err
 }
 
-// install helm chart from given directory and with given values to cluster
-func InstallWithOpts(cluster *k8t.Cluster, dir string, values Value, opts InstallOpts) error {
+// Install helm chart from given directory and with given values to cluster.
+// The function returns objects rendered by the release, so you can wait for
+// them:
+//
+//	objs, err := helm.InstallWithOpts(cluster, "testdata/my-helm", vals, helm.InstallOpts{})
+//	err = cluster.WaitForAllCurrent(objs)
+func InstallWithOpts(cluster *k8t.Cluster, dir string, values Value, opts InstallOpts) ([]*unstructured.Unstructured, error) {
 	ctx := opts.Context
 	if ctx == nil {
 		ctx = context.Background()
@@ -54,7 +61,7 @@ func InstallWithOpts(cluster *k8t.Cluster, dir string, values Value, opts Instal
 	if filesystem == nil {
 		wd, err := os.Getwd()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		filesystem = os.DirFS(wd)
@@ -63,7 +70,7 @@ func InstallWithOpts(cluster *k8t.Cluster, dir string, values Value, opts Instal
 	// load chart
 	chart, err := loadChartFromFS(filesystem, dir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	releaseName := opts.ReleaseName
@@ -77,7 +84,7 @@ func InstallWithOpts(cluster *k8t.Cluster, dir string, values Value, opts Instal
 		fmt.Printf(format, v...)
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	client := action.NewInstall(cfg)
@@ -88,9 +95,23 @@ func InstallWithOpts(cluster *k8t.Cluster, dir string, values Value, opts Instal
 	// run installation
 	rel, err := client.RunWithContext(ctx, chart, values)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	fmt.Printf("installed %s into %s\n", rel.Name, rel.Namespace)
-	return nil
+
+	objs, err := k8t.DecodeManifest(rel.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	// templates usually don't have namespace, helm installs them into
+	// release's namespace
+	for _, obj := range objs {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(rel.Namespace)
+		}
+	}
+
+	return objs, nil
 }
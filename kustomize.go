@@ -0,0 +1,136 @@
+package k8t
+
+import (
+	"io/fs"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// Build kustomization in given local directory and apply the resulting
+// objects. It's in-process equivalent of 'kubectl apply -k dir', no
+// kubectl or kustomize binary is needed.
+func (c *Cluster) ApplyKustomize(dir string) error {
+	_, err := c.ApplyKustomizeWithOpts(dir, ApplyOpts{})
+	return err
+}
+
+// More verbose version of ApplyKustomize() function where you can pass
+// your own context or set namespace.
+func (c *Cluster) ApplyKustomizeWithOpts(dir string, opts ApplyOpts) (ApplyResult, error) {
+	objs, err := buildKustomization(filesys.MakeFsOnDisk(), dir)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+
+	return c.applyObjects(objs, opts)
+}
+
+// Build kustomization in given directory of filesystem (e.g. embed.FS) and
+// apply the resulting objects. All the bases and resources referenced by
+// the kustomization must be in the same filesystem.
+func (c *Cluster) ApplyKustomizeFS(fsys fs.FS, dir string) error {
+	_, err := c.ApplyKustomizeFSWithOpts(fsys, dir, ApplyOpts{})
+	return err
+}
+
+// More verbose version of ApplyKustomizeFS() function where you can pass
+// your own context or set namespace.
+func (c *Cluster) ApplyKustomizeFSWithOpts(fsys fs.FS, dir string, opts ApplyOpts) (ApplyResult, error) {
+	kfs, err := copyToKustomizeFS(fsys)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+
+	objs, err := buildKustomization(kfs, dir)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+
+	return c.applyObjects(objs, opts)
+}
+
+// Build kustomization in given local directory and delete the resulting
+// objects. It's counterpart of ApplyKustomize().
+func (c *Cluster) DeleteKustomize(dir string) error {
+	return c.DeleteKustomizeWithOpts(dir, DeleteOpts{})
+}
+
+// More verbose version of DeleteKustomize() function that allow you pass
+// the context or change the namespace etc.
+func (c *Cluster) DeleteKustomizeWithOpts(dir string, opts DeleteOpts) error {
+	objs, err := buildKustomization(filesys.MakeFsOnDisk(), dir)
+	if err != nil {
+		return err
+	}
+
+	return c.deleteObjects(objs, opts)
+}
+
+// Build kustomization in given directory of filesystem and delete the
+// resulting objects. It's counterpart of ApplyKustomizeFS().
+func (c *Cluster) DeleteKustomizeFS(fsys fs.FS, dir string) error {
+	return c.DeleteKustomizeFSWithOpts(fsys, dir, DeleteOpts{})
+}
+
+// More verbose version of DeleteKustomizeFS() function that allow you pass
+// the context or change the namespace etc.
+func (c *Cluster) DeleteKustomizeFSWithOpts(fsys fs.FS, dir string, opts DeleteOpts) error {
+	kfs, err := copyToKustomizeFS(fsys)
+	if err != nil {
+		return err
+	}
+
+	objs, err := buildKustomization(kfs, dir)
+	if err != nil {
+		return err
+	}
+
+	return c.deleteObjects(objs, opts)
+}
+
+// run kustomize build on given directory and decode the result into objects
+func buildKustomization(kfs filesys.FileSystem, dir string) ([]*unstructured.Unstructured, error) {
+	k := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	resMap, err := k.Run(kfs, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	yml, err := resMap.AsYaml()
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeManifest(string(yml))
+}
+
+// kustomize is using own filesystem abstraction. This function copy all
+// files from given fs.FS into in-memory kustomize filesystem.
+func copyToKustomizeFS(fsys fs.FS) (filesys.FileSystem, error) {
+	kfs := filesys.MakeFsInMemory()
+
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return kfs.MkdirAll(path)
+		}
+
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+
+		return kfs.WriteFile(path, data)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return kfs, nil
+}
@@ -0,0 +1,31 @@
+package k8t
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func Test_buildKustomization(t *testing.T) {
+	// GIVEN: overlay which is patching the base
+	fsys := fstest.MapFS{
+		"base/kustomization.yaml":    {Data: []byte("resources:\n- configmap.yaml\n")},
+		"base/configmap.yaml":        {Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  env: base\n")},
+		"overlay/kustomization.yaml": {Data: []byte("resources:\n- ../base\nnamePrefix: dev-\n")},
+	}
+
+	kfs, err := copyToKustomizeFS(fsys)
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I build the overlay
+	objs, err := buildKustomization(kfs, "overlay")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: I get the object from base with the prefix
+	if len(objs) != 1 || objectRef(objs[0]) != "ConfigMap/dev-config" {
+		t.FailNow()
+	}
+}
@@ -5,10 +5,11 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/dynamic"
 )
 
+// objects are listed in pages of this size
+const listPageSize = 500
+
 // Optional options for ListWithOpts function
 type ListOpts struct {
 	// here you can pass your context. It it's not set, the default
@@ -16,8 +17,20 @@ type ListOpts struct {
 	Context context.Context
 
 	// namespace where is the resource. If it's not set, the cluster's
-	// testNamespace will be used
+	// testNamespace will be used. This is ignored for cluster-wide resources
 	Namespace string
+
+	// if it's set, objects from all namespaces are listed and Namespace
+	// is ignored
+	AllNamespaces bool
+
+	// field selector, e.g. 'spec.nodeName=worker-1' or 'status.phase=Running'
+	FieldSelector string
+
+	// maximum number of objects returned. If it's not set, all the objects
+	// are returned. The objects are fetched in pages, continue tokens are
+	// handled automatically.
+	Limit int64
 }
 
 // More verbose version of List() function. Use this function if you want to
@@ -40,32 +53,47 @@ func (c *Cluster) ListWithOpts(apiVersion, kind, labelSelector string, opts List
 		namespace = c.testNamespace
 	}
 
-	// we need to parse and convert apiVersion and Kind into
-	// GroupVersionResource
-	gvk := schema.FromAPIVersionAndKind(apiVersion, kind)
-
-	gk := schema.GroupKind{
-		Group: gvk.Group,
-		Kind:  gvk.Kind,
+	// empty namespace means all namespaces for namespaced resources
+	if opts.AllNamespaces {
+		namespace = ""
 	}
 
-	rm, err := c.restMapper.RESTMapping(gk, gvk.Version)
+	dr, err := c.resourceFor(apiVersion, kind, namespace)
 	if err != nil {
 		return nil, err
 	}
 
-	d, err := dynamic.NewForConfig(c.restConfig)
-	if err != nil {
-		return nil, err
+	listOpts := metav1.ListOptions{
+		LabelSelector: labelSelector,
+		FieldSelector: opts.FieldSelector,
+		Limit:         listPageSize,
 	}
 
-	res, err := d.Resource(rm.Resource).Namespace(c.testNamespace).List(ctx, metav1.ListOptions{
-		LabelSelector: labelSelector,
-	})
+	res := &unstructured.UnstructuredList{}
+	for {
+		if opts.Limit > 0 && opts.Limit-int64(len(res.Items)) < listPageSize {
+			listOpts.Limit = opts.Limit - int64(len(res.Items))
+		}
 
-	if err != nil {
-		return nil, err
+		page, err := dr.List(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		// list's metadata (e.g. resourceVersion) are from the first page
+		if listOpts.Continue == "" {
+			res.Object = page.Object
+		}
+		res.Items = append(res.Items, page.Items...)
+
+		listOpts.Continue = page.GetContinue()
+		if listOpts.Continue == "" || (opts.Limit > 0 && int64(len(res.Items)) >= opts.Limit) {
+			break
+		}
 	}
 
+	// all pages are fetched, there is nothing to continue
+	res.SetContinue("")
+
 	return res, nil
 }
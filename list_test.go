@@ -35,3 +35,36 @@ func Test_List(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func Test_ListAllNamespaces(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I list running pods in all namespaces with limit
+	res, err := cluster.ListWithOpts("v1", "Pod", "", k8t.ListOpts{
+		AllNamespaces: true,
+		FieldSelector: "status.phase=Running",
+		Limit:         2,
+	})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: I'll get at most 2 running pods (there are always system pods)
+	if len(res.Items) == 0 || len(res.Items) > 2 {
+		t.FailNow()
+	}
+
+	// AND: cluster-wide resources can be listed too
+	nodes, err := cluster.List("v1", "Node", "")
+	if err != nil || len(nodes.Items) == 0 {
+		t.FailNow()
+	}
+}
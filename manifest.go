@@ -0,0 +1,136 @@
+package k8t
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Decode YAML or JSON manifest into list of unstructured objects. The
+// manifest can contain multiple documents separated by '---' and also
+// 'kind: List' objects. Lists are flattened into their items. Empty
+// documents are skipped.
+func DecodeManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	objs := make([]*unstructured.Unstructured, 0)
+
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+	for doc := 1; ; doc++ {
+		obj := &unstructured.Unstructured{}
+		err := decoder.Decode(&obj.Object)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", doc, err)
+		}
+
+		// skip empty documents, e.g. '---' at the end of file
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		if obj.GetKind() == "" {
+			return nil, fmt.Errorf("document %d: object has no kind", doc)
+		}
+
+		if !obj.IsList() {
+			objs = append(objs, obj)
+			continue
+		}
+
+		err = obj.EachListItem(func(item runtime.Object) error {
+			u, ok := item.(*unstructured.Unstructured)
+			if !ok {
+				return fmt.Errorf("unexpected list item %T", item)
+			}
+			objs = append(objs, u)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", doc, err)
+		}
+	}
+
+	return objs, nil
+}
+
+// returns human readable reference of object like 'Deployment/my-app'
+// used in error messages
+func objectRef(obj *unstructured.Unstructured) string {
+	return obj.GetKind() + "/" + obj.GetName()
+}
+
+// check if file is manifest by its extension
+func isManifestFile(name string) bool {
+	switch path.Ext(name) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+// load all manifests matching given glob pattern from filesystem and decode
+// them into objects. Directories matching the pattern are walked recursively.
+// Only '.yaml', '.yml' and '.json' files are loaded. Files are processed in
+// lexical order, so the result is deterministic.
+func loadManifestsFromFS(fsys fs.FS, glob string) ([]*unstructured.Unstructured, error) {
+	if fsys == nil {
+		return nil, fmt.Errorf("no filesystem")
+	}
+
+	if glob == "" {
+		glob = "."
+	}
+
+	matches, err := fs.Glob(fsys, glob)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no manifests matching '%s'", glob)
+	}
+
+	files := make([]string, 0)
+	for _, match := range matches {
+		err := fs.WalkDir(fsys, match, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !d.IsDir() && isManifestFile(path) {
+				files = append(files, path)
+			}
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+
+	objs := make([]*unstructured.Unstructured, 0)
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		fileObjs, err := DecodeManifest(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		objs = append(objs, fileObjs...)
+	}
+
+	return objs, nil
+}
@@ -0,0 +1,120 @@
+package k8t
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func Test_DecodeManifest(t *testing.T) {
+	// GIVEN: manifest with multiple documents and list
+	manifest := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: second
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: third
+---
+`
+
+	// WHEN: I decode the manifest
+	objs, err := DecodeManifest(manifest)
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: I get all objects, empty documents are skipped and list is flattened
+	if len(objs) != 3 {
+		t.FailNow()
+	}
+
+	if objectRef(objs[0]) != "ConfigMap/first" || objectRef(objs[1]) != "Service/second" || objectRef(objs[2]) != "Deployment/third" {
+		t.FailNow()
+	}
+}
+
+func Test_DecodeManifest_NoKind(t *testing.T) {
+	// GIVEN: manifest where second document has no kind
+	manifest := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+metadata:
+  name: second
+`
+
+	// WHEN: I decode the manifest
+	_, err := DecodeManifest(manifest)
+
+	// THEN: I get an error
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func Test_loadManifestsFromFS(t *testing.T) {
+	// GIVEN: filesystem with manifests in nested directories and some
+	// other files
+	fsys := fstest.MapFS{
+		"fixtures/b.yaml":        {Data: []byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: b\n")},
+		"fixtures/a.yml":         {Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n")},
+		"fixtures/nested/c.json": {Data: []byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "c"}}`)},
+		"fixtures/README.md":     {Data: []byte("# not a manifest")},
+	}
+
+	// WHEN: I load manifests from the directory
+	objs, err := loadManifestsFromFS(fsys, "fixtures")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: I get objects from all manifests in lexical order
+	expected := []string{"ConfigMap/a", "Service/b", "Secret/c"}
+	if len(objs) != len(expected) {
+		t.FailNow()
+	}
+
+	for i := range expected {
+		if objectRef(objs[i]) != expected[i] {
+			t.FailNow()
+		}
+	}
+}
+
+func Test_loadManifestsFromFS_Glob(t *testing.T) {
+	// GIVEN: filesystem with YAML and JSON manifests
+	fsys := fstest.MapFS{
+		"a.yaml": {Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n")},
+		"b.json": {Data: []byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "b"}}`)},
+	}
+
+	// WHEN: I load manifests matching glob
+	objs, err := loadManifestsFromFS(fsys, "*.yaml")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: I get only the matching manifest
+	if len(objs) != 1 || objectRef(objs[0]) != "ConfigMap/a" {
+		t.FailNow()
+	}
+
+	// AND: glob without match ends with error
+	_, err = loadManifestsFromFS(fsys, "*.yml")
+	if err == nil {
+		t.FailNow()
+	}
+}
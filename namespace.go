@@ -0,0 +1,129 @@
+package k8t
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Optional options for NewTestNamespaceWithOpts function
+type TestNamespaceOpts struct {
+	// here you can pass your context. It it's not set, the default
+	// context.Background() will be used
+	Context context.Context
+
+	// labels added to created namespace. It's handy when you want to
+	// find namespaces created by your test run later.
+	Labels map[string]string
+}
+
+// Optional options for CleanupWithOpts function
+type CleanupOpts struct {
+	// here you can pass your context. It it's not set, the default
+	// context.Background() will be used
+	Context context.Context
+
+	// maximum duration for which the function will wait until namespace
+	// is terminated. If it's not set, the default 2 minutes will be used
+	Timeout time.Duration
+}
+
+// Returns derived cluster, where all the functions (Apply, Get, List, Exec,
+// helm's Install etc.) are working with given namespace as test namespace.
+// The original cluster is not modified.
+func (c *Cluster) WithNamespace(namespace string) *Cluster {
+	derived := *c
+	derived.testNamespace = namespace
+	derived.ephemeralNamespace = ""
+	return &derived
+}
+
+// Less verbose version of NewTestNamespaceWithOpts.
+func (c *Cluster) NewTestNamespace(prefix string) (*Cluster, error) {
+	return c.NewTestNamespaceWithOpts(prefix, TestNamespaceOpts{})
+}
+
+// Creates new namespace with unique name starting with given prefix and
+// returns derived cluster scoped to this namespace. Each test can have
+// own namespace, so parallel tests and repeated CI runs don't collide.
+//
+// When test is done, call Cleanup() on the derived cluster, which
+// deletes the namespace and waits until it's terminated:
+//
+//	ns, err := cluster.NewTestNamespace("my-test")
+//	defer ns.Cleanup()
+func (c *Cluster) NewTestNamespaceWithOpts(prefix string, opts TestNamespaceOpts) (*Cluster, error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if prefix == "" {
+		prefix = "k8t"
+	}
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: prefix + "-",
+			Labels:       opts.Labels,
+		},
+	}
+
+	created, err := c.k8sClient.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	derived := c.WithNamespace(created.Name)
+	derived.ephemeralNamespace = created.Name
+	return derived, nil
+}
+
+// Less verbose version of CleanupWithOpts with default timeout.
+func (c *Cluster) Cleanup() error {
+	return c.CleanupWithOpts(CleanupOpts{})
+}
+
+// Deletes the namespace created by NewTestNamespace() and waits until the
+// namespace is really gone. If cluster wasn't created by NewTestNamespace(),
+// function does nothing.
+func (c *Cluster) CleanupWithOpts(opts CleanupOpts) error {
+	if c.ephemeralNamespace == "" {
+		return nil
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err := c.k8sClient.CoreV1().Namespaces().Delete(ctx, c.ephemeralNamespace, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	err = c.WaitForWithOpts(namespaceNotExist(c.ephemeralNamespace), WaitForOpts{
+		Context: ctx,
+		Timeout: opts.Timeout,
+	})
+	if err != nil {
+		return fmt.Errorf("namespace %s is not terminated: %w", c.ephemeralNamespace, err)
+	}
+
+	return nil
+}
+
+// check if namespace is gone, not only in terminating phase
+func namespaceNotExist(name string) Checker {
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		_, err := c.k8sClient.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+}
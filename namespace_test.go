@@ -0,0 +1,54 @@
+package k8t_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sn3d/k8t"
+)
+
+func Test_NewTestNamespace(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I create new test namespace
+	ns, err := cluster.NewTestNamespace("ns-test")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: derived cluster is scoped to unique namespace
+	if !strings.HasPrefix(ns.TestNamespace(), "ns-test-") {
+		t.FailNow()
+	}
+
+	// AND: I can apply resources into it
+	err = ns.ApplyFile("testdata/simple-service.yaml")
+	if err != nil {
+		t.FailNow()
+	}
+
+	_, err = ns.Get("v1", "Service", "echo-service")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// AND: cleanup removes the namespace
+	err = ns.Cleanup()
+	if err != nil {
+		t.FailNow()
+	}
+
+	err = cluster.WaitFor(k8t.ResourceNotExist("v1", "Namespace", ns.TestNamespace()))
+	if err != nil {
+		t.FailNow()
+	}
+}
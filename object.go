@@ -0,0 +1,73 @@
+package k8t
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+)
+
+// Apply typed Go object (e.g. *corev1.Pod, *appsv1.Deployment) or
+// *unstructured.Unstructured into cluster's test namespace. The apiVersion
+// and kind are resolved from the scheme, so you don't need to set them.
+//
+// After successful apply, the given object is updated with the live object
+// from the server, including UID, resourceVersion and defaulted fields. The
+// same object can be modified and applied again, the fields maintained by
+// server are not sent.
+func (c *Cluster) ApplyObject(obj runtime.Object) error {
+	return c.ApplyObjectWithOpts(obj, ApplyOpts{})
+}
+
+// More verbose version of ApplyObject() function where you can pass your own
+// context or set namespace.
+func (c *Cluster) ApplyObjectWithOpts(obj runtime.Object, opts ApplyOpts) error {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	u = stripForApply(u)
+
+	dyn, err := dynamic.NewForConfig(c.restConfig)
+	if err != nil {
+		return err
+	}
+
+	_, live, err := applyObject(ctx, dyn, c.restMapper, u, opts.Namespace, c.testNamespace, opts.patchOptions())
+	if err != nil {
+		return err
+	}
+
+	if isCRD(u) && !opts.DryRun {
+		_, err = c.waitForCRDs(ctx, []*unstructured.Unstructured{u})
+		if err != nil {
+			return err
+		}
+	}
+
+	return fromUnstructured(live, obj)
+}
+
+// Delete typed Go object or *unstructured.Unstructured from cluster's test
+// namespace. Only apiVersion, kind, name and namespace of the object
+// are used.
+func (c *Cluster) DeleteObject(obj runtime.Object) error {
+	return c.DeleteObjectWithOpts(obj, DeleteOpts{})
+}
+
+// More verbose version of DeleteObject() function that allow you pass the
+// context or change the namespace etc.
+func (c *Cluster) DeleteObjectWithOpts(obj runtime.Object, opts DeleteOpts) error {
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return err
+	}
+
+	return c.deleteObjects([]*unstructured.Unstructured{u}, opts)
+}
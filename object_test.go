@@ -0,0 +1,62 @@
+package k8t_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sn3d/k8t"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ApplyObject(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	// AND: config map built in Go
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "typed-config"},
+		Data:       map[string]string{"greeting": "hello"},
+	}
+
+	// WHEN: I apply the object
+	err = cluster.ApplyObject(cm)
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: the object is updated with live state from server
+	if cm.UID == "" || cm.ResourceVersion == "" {
+		t.FailNow()
+	}
+
+	// WHEN: I modify the same object and apply it again
+	cm.Data["greeting"] = "hi"
+	err = cluster.ApplyObject(cm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: the change is applied
+	if cm.Data["greeting"] != "hi" {
+		t.FailNow()
+	}
+
+	// AND: I can delete it
+	err = cluster.DeleteObject(cm)
+	if err != nil {
+		t.FailNow()
+	}
+
+	err = cluster.WaitFor(k8t.ResourceNotExist("v1", "ConfigMap", "typed-config"))
+	if err != nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,113 @@
+package k8t
+
+import (
+	"context"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// the order in which kinds are applied. Objects other objects depend on
+// (namespaces, CRDs, RBAC, configuration) go first, workloads after them.
+// Kinds which are not in the list (typically custom resources) are applied
+// at the end. Delete is using reversed order.
+var applyOrder = []string{
+	"Namespace",
+	"ResourceQuota",
+	"LimitRange",
+	"PodSecurityPolicy",
+	"PodDisruptionBudget",
+	"CustomResourceDefinition",
+	"PriorityClass",
+	"StorageClass",
+	"ServiceAccount",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"ConfigMap",
+	"Secret",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"NetworkPolicy",
+	"APIService",
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+}
+
+// returns position of object's kind in applyOrder. Unknown kinds
+// have the highest rank.
+func applyRank(obj *unstructured.Unstructured) int {
+	for i, kind := range applyOrder {
+		if obj.GetKind() == kind {
+			return i
+		}
+	}
+	return len(applyOrder)
+}
+
+// returns copy of objects sorted for apply. Objects of the same kind
+// keep their order from the manifest.
+func sortForApply(objs []*unstructured.Unstructured) []*unstructured.Unstructured {
+	sorted := append([]*unstructured.Unstructured{}, objs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return applyRank(sorted[i]) < applyRank(sorted[j])
+	})
+	return sorted
+}
+
+// returns copy of objects sorted for delete, that is reversed
+// order than apply
+func sortForDelete(objs []*unstructured.Unstructured) []*unstructured.Unstructured {
+	sorted := append([]*unstructured.Unstructured{}, objs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return applyRank(sorted[i]) > applyRank(sorted[j])
+	})
+	return sorted
+}
+
+// check if object is CustomResourceDefinition
+func isCRD(obj *unstructured.Unstructured) bool {
+	return obj.GroupVersionKind().Group == "apiextensions.k8s.io" && obj.GetKind() == "CustomResourceDefinition"
+}
+
+// check if CRD of given name is established, which means the API server
+// is serving the new kind
+func crdIsEstablished(name string) Checker {
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		crd, err := c.GetWithOpts("apiextensions.k8s.io/v1", "CustomResourceDefinition", name, GetOpts{Context: ctx})
+		if err != nil {
+			return false, err
+		}
+
+		conditions, _, err := unstructured.NestedSlice(crd.Object, "status", "conditions")
+		if err != nil {
+			return false, err
+		}
+
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if condition["type"] == "Established" && condition["status"] == "True" {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
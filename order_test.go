@@ -0,0 +1,76 @@
+package k8t
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_sortForApply(t *testing.T) {
+	// GIVEN: objects in order where dependencies are at the end
+	objs := []*unstructured.Unstructured{
+		newTestObject("example.com/v1", "Database", "db"),
+		newTestObject("apps/v1", "Deployment", "app"),
+		newTestObject("v1", "ConfigMap", "config"),
+		newTestObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "databases.example.com"),
+		newTestObject("v1", "Namespace", "ns"),
+	}
+
+	// WHEN: I sort them for apply
+	sorted := sortForApply(objs)
+
+	// THEN: namespace and CRD go first and custom resource at the end
+	expected := []string{"Namespace/ns", "CustomResourceDefinition/databases.example.com", "ConfigMap/config", "Deployment/app", "Database/db"}
+	for i := range expected {
+		if objectRef(sorted[i]) != expected[i] {
+			t.FailNow()
+		}
+	}
+
+	// AND: the original slice is not modified
+	if objectRef(objs[0]) != "Database/db" {
+		t.FailNow()
+	}
+}
+
+func Test_sortForDelete(t *testing.T) {
+	// GIVEN: objects in apply order
+	objs := []*unstructured.Unstructured{
+		newTestObject("v1", "Namespace", "ns"),
+		newTestObject("v1", "Service", "first"),
+		newTestObject("v1", "Service", "second"),
+		newTestObject("example.com/v1", "Database", "db"),
+	}
+
+	// WHEN: I sort them for delete
+	sorted := sortForDelete(objs)
+
+	// THEN: the order is reversed, but the same kinds keep their order
+	expected := []string{"Database/db", "Service/first", "Service/second", "Namespace/ns"}
+	for i := range expected {
+		if objectRef(sorted[i]) != expected[i] {
+			t.FailNow()
+		}
+	}
+}
+
+func Test_crdGroupKind(t *testing.T) {
+	// GIVEN: CRD which defines Greeting kind
+	crd := newTestObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "greetings.k8t.sn3d.com")
+	unstructured.SetNestedField(crd.Object, "k8t.sn3d.com", "spec", "group")
+	unstructured.SetNestedField(crd.Object, "Greeting", "spec", "names", "kind")
+
+	// THEN: I get the group and kind of custom resources
+	gk := crdGroupKind(crd)
+	if gk.Group != "k8t.sn3d.com" || gk.Kind != "Greeting" {
+		t.FailNow()
+	}
+}
+
+func newTestObject(apiVersion, kind, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	return obj
+}
@@ -0,0 +1,86 @@
+package k8t
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// supported types of patch for Patch() function
+const (
+	// JSON patch (RFC 6902), list of operations like
+	// '[{"op": "replace", "path": "/spec/replicas", "value": 3}]'
+	JSONPatch = types.JSONPatchType
+
+	// JSON merge patch (RFC 7386), e.g. '{"spec": {"replicas": 3}}'
+	MergePatch = types.MergePatchType
+
+	// Kubernetes strategic merge patch. It's like merge patch, but lists
+	// are merged by their keys (e.g. containers by name). It's not supported
+	// for custom resources.
+	StrategicMergePatch = types.StrategicMergePatchType
+)
+
+// Optional options for PatchWithOpts function
+type PatchOpts struct {
+	// here you can pass your context. It it's not set, the default
+	// context.Background() will be used
+	Context context.Context
+
+	// namespace where is the resource. If it's not set, the cluster's
+	// testNamespace will be used
+	Namespace string
+
+	// subresource to patch, e.g. 'status' or 'scale'. If it's not set,
+	// the resource itself is patched.
+	Subresource string
+}
+
+// Patch the resource of given apiVersion, kind and name in test namespace.
+// The patch can be written in JSON or YAML, it's converted into JSON before
+// it's sent to server. Function returns the patched object:
+//
+//	cluster.Patch("apps/v1", "Deployment", "my-app", k8t.MergePatch, `
+//	spec:
+//	  replicas: 3
+//	`)
+func (c *Cluster) Patch(apiVersion, kind, name string, patchType types.PatchType, patch string) (*unstructured.Unstructured, error) {
+	return c.PatchWithOpts(apiVersion, kind, name, patchType, patch, PatchOpts{})
+}
+
+// More verbose version of Patch() function. Use this function if you want to
+// pass own context, specify the namespace or patch the subresource
+func (c *Cluster) PatchWithOpts(apiVersion, kind, name string, patchType types.PatchType, patch string, opts PatchOpts) (*unstructured.Unstructured, error) {
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = c.testNamespace
+	}
+
+	data, err := yaml.YAMLToJSON([]byte(patch))
+	if err != nil {
+		return nil, err
+	}
+
+	dr, err := c.resourceFor(apiVersion, kind, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	subresources := make([]string, 0)
+	if opts.Subresource != "" {
+		subresources = append(subresources, opts.Subresource)
+	}
+
+	return dr.Patch(ctx, name, patchType, data, metav1.PatchOptions{
+		FieldManager: "k8t",
+	}, subresources...)
+}
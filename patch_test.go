@@ -0,0 +1,54 @@
+package k8t_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sn3d/k8t"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_Patch(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	// AND: deployed service
+	err = cluster.ApplyFile("testdata/simple-service.yaml")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I add label with merge patch
+	res, err := cluster.Patch("v1", "Service", "echo-service", k8t.MergePatch, `
+metadata:
+  labels:
+    patched: "yes"
+`)
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: the returned object has the label
+	if res.GetLabels()["patched"] != "yes" {
+		t.FailNow()
+	}
+
+	// WHEN: I change the port with JSON patch
+	res, err = cluster.Patch("v1", "Service", "echo-service", k8t.JSONPatch, `[{"op": "replace", "path": "/spec/ports/0/port", "value": 8080}]`)
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: the port is changed
+	ports, _, _ := unstructured.NestedSlice(res.Object, "spec", "ports")
+	if len(ports) != 1 || ports[0].(map[string]interface{})["port"] != int64(8080) {
+		t.FailNow()
+	}
+}
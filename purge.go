@@ -0,0 +1,197 @@
+package k8t
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// options for DeleteAll and PurgeNamespaceWithOpts functions
+type PurgeOpts struct {
+	// options for deleting of matching objects. The namespace is ignored
+	// by PurgeNamespaceWithOpts()
+	DeleteOpts
+
+	// allows empty label selector, which deletes all the objects. Without
+	// it, the empty label selector is rejected.
+	All bool
+}
+
+// Delete all resources of given apiVersion and kind matching the label
+// selector in test namespace. It's handy for cleanup after test, e.g.
+// delete everything labelled 'test-run=abc':
+//
+//	cluster.DeleteAll("v1", "ConfigMap", "test-run=abc", k8t.PurgeOpts{})
+//
+// The function is using DeleteCollection. For resources which don't support
+// it, the objects are deleted one by one. The returned error contains all
+// the objects which failed.
+//
+// Empty label selector is rejected, because it would delete all the objects
+// of given kind. If you really want it, set the opts.All.
+func (c *Cluster) DeleteAll(apiVersion, kind, labelSelector string, opts PurgeOpts) error {
+	if labelSelector == "" && !opts.All {
+		return errEmptySelector
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = c.testNamespace
+	}
+
+	dr, err := c.resourceFor(apiVersion, kind, namespace)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := deleteSelected(ctx, dr, labelSelector, opts.DeleteOpts)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(c.waitForDeletions(ctx, deleted, opts.DeleteOpts)...)
+}
+
+// Less verbose version of PurgeNamespaceWithOpts.
+func (c *Cluster) PurgeNamespace(namespace, labelSelector string) error {
+	return c.PurgeNamespaceWithOpts(namespace, labelSelector, PurgeOpts{})
+}
+
+// Delete all objects matching the label selector in given namespace. If
+// namespace is empty, the cluster's test namespace will be used. The function
+// walks all namespaced resources found through discovery, which can be
+// listed and deleted, including custom resources. The returned error
+// contains all the objects which failed.
+//
+// Empty label selector is rejected, because it would delete also objects
+// managed by the system, like default ServiceAccount or 'kube-root-ca.crt'
+// ConfigMap. If you really want it, set the opts.All.
+//
+// The namespace in opts is ignored.
+func (c *Cluster) PurgeNamespaceWithOpts(namespace, labelSelector string, opts PurgeOpts) error {
+	if labelSelector == "" && !opts.All {
+		return errEmptySelector
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if namespace == "" {
+		namespace = c.testNamespace
+	}
+
+	// some API groups can be unavailable (e.g. broken metrics server), we
+	// purge at least resources we know about
+	resources, err := c.k8sClient.Discovery().ServerPreferredNamespacedResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return err
+	}
+
+	resources = discovery.FilteredBy(discovery.SupportsAllVerbs{Verbs: []string{"list", "delete"}}, resources)
+
+	gvrs, err := discovery.GroupVersionResources(resources)
+	if err != nil {
+		return err
+	}
+
+	dyn, err := dynamic.NewForConfig(c.restConfig)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, 0)
+	deleted := make([]deletedObject, 0)
+	for gvr := range gvrs {
+		d, err := deleteSelected(ctx, dyn.Resource(gvr).Namespace(namespace), labelSelector, opts.DeleteOpts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", gvr.GroupResource(), err))
+		}
+		deleted = append(deleted, d...)
+	}
+
+	errs = append(errs, c.waitForDeletions(ctx, deleted, opts.DeleteOpts)...)
+	return errors.Join(errs...)
+}
+
+// error for DeleteAll and PurgeNamespace called without selector
+var errEmptySelector = errors.New("empty label selector would delete all objects, set PurgeOpts.All if it's intended")
+
+// delete all objects matching the label selector with DeleteCollection. The
+// objects are listed first, so we know what to wait for. The collection is
+// deleted at exactly the list's resourceVersion, so the objects created
+// after the list are not touched. Resources which don't support
+// DeleteCollection fall back to deleting the listed objects one by one.
+// Function returns deleted objects, so the caller can wait for them.
+func deleteSelected(ctx context.Context, dr dynamic.ResourceInterface, labelSelector string, opts DeleteOpts) ([]deletedObject, error) {
+	list, err := dr.List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+
+	err = dr.DeleteCollection(ctx, opts.deleteOptions(), metav1.ListOptions{
+		LabelSelector:        labelSelector,
+		ResourceVersion:      list.GetResourceVersion(),
+		ResourceVersionMatch: metav1.ResourceVersionMatchExact,
+	})
+	if apierrors.IsMethodNotSupported(err) {
+		return deleteListed(ctx, dr, list, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]deletedObject, 0, len(list.Items))
+	for i := range list.Items {
+		obj := &list.Items[i]
+		deleted = append(deleted, deletedObject{obj: obj, dr: dr, uid: obj.GetUID()})
+	}
+	return deleted, nil
+}
+
+// delete listed objects one by one, by name with UID precondition, so the
+// objects recreated with the same name are not touched.
+func deleteListed(ctx context.Context, dr dynamic.ResourceInterface, list *unstructured.UnstructuredList, opts DeleteOpts) ([]deletedObject, error) {
+	errs := make([]error, 0)
+	deleted := make([]deletedObject, 0, len(list.Items))
+	for i := range list.Items {
+		obj := &list.Items[i]
+
+		deleteOpts := opts.deleteOptions()
+		uid := obj.GetUID()
+		deleteOpts.Preconditions = &metav1.Preconditions{UID: &uid}
+
+		err := dr.Delete(ctx, obj.GetName(), deleteOpts)
+		if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+			// object is already gone, or it's another object with same name
+			continue
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", objectRef(obj), err))
+			continue
+		}
+
+		deleted = append(deleted, deletedObject{obj: obj, dr: dr, uid: uid})
+	}
+
+	return deleted, errors.Join(errs...)
+}
@@ -0,0 +1,72 @@
+package k8t
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newPurgeTestClient() *dynamicfake.FakeDynamicClient {
+	objs := make([]runtime.Object, 0)
+	for _, name := range []string{"first", "second"} {
+		obj := newTestConfigMap(name, "1")
+		obj.SetUID(types.UID("uid-" + name))
+		obj.SetLabels(map[string]string{"test-run": "purge"})
+		objs = append(objs, obj)
+	}
+
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		configMapsGVR: "ConfigMapList",
+	}, objs...)
+}
+
+func Test_deleteSelected(t *testing.T) {
+	// GIVEN: labelled config maps
+	dyn := newPurgeTestClient()
+	dr := dyn.Resource(configMapsGVR).Namespace("test")
+
+	// WHEN: I delete them
+	deleted, err := deleteSelected(context.Background(), dr, "test-run=purge", DeleteOpts{})
+
+	// THEN: both are deleted with DeleteCollection and returned for waiting
+	if err != nil || len(deleted) != 2 {
+		t.Fatal(err)
+	}
+
+	collection := false
+	for _, action := range dyn.Actions() {
+		collection = collection || action.GetVerb() == "delete-collection"
+	}
+	if !collection {
+		t.FailNow()
+	}
+}
+
+func Test_deleteSelectedFallback(t *testing.T) {
+	// GIVEN: labelled config maps, where DeleteCollection is not supported
+	dyn := newPurgeTestClient()
+	dyn.PrependReactor("delete-collection", "configmaps", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewMethodNotSupported(configMapsGVR.GroupResource(), "deletecollection")
+	})
+	dr := dyn.Resource(configMapsGVR).Namespace("test")
+
+	// WHEN: I delete them
+	deleted, err := deleteSelected(context.Background(), dr, "test-run=purge", DeleteOpts{})
+
+	// THEN: they're deleted one by one
+	if err != nil || len(deleted) != 2 {
+		t.Fatal(err)
+	}
+
+	list, err := dr.List(context.Background(), metav1.ListOptions{})
+	if err != nil || len(list.Items) != 0 {
+		t.FailNow()
+	}
+}
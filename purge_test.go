@@ -0,0 +1,115 @@
+package k8t_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sn3d/k8t"
+)
+
+func Test_DeleteAll(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster with labelled config maps
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	err = cluster.ApplyTemplate(`
+{{- range .Names }}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ . }}
+  labels:
+    test-run: delete-all
+{{- end }}
+`, map[string]any{"Names": []string{"first", "second", "third"}})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I delete all config maps with the label
+	err = cluster.DeleteAll("v1", "ConfigMap", "test-run=delete-all", k8t.PurgeOpts{DeleteOpts: k8t.DeleteOpts{Wait: true}})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: there are no config maps with the label
+	res, err := cluster.List("v1", "ConfigMap", "test-run=delete-all")
+	if err != nil {
+		t.FailNow()
+	}
+
+	if len(res.Items) != 0 {
+		t.FailNow()
+	}
+}
+
+func Test_PurgeNamespace(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster with labelled objects of different kinds
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	err = cluster.Apply(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: purge-config
+  labels:
+    test-run: purge
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: purge-secret
+  labels:
+    test-run: purge
+`)
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I purge the namespace
+	err = cluster.PurgeNamespaceWithOpts("", "test-run=purge", k8t.PurgeOpts{DeleteOpts: k8t.DeleteOpts{Wait: true}})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: all the objects are gone
+	_, err = cluster.Get("v1", "ConfigMap", "purge-config")
+	if err == nil {
+		t.FailNow()
+	}
+
+	_, err = cluster.Get("v1", "Secret", "purge-secret")
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func Test_DeleteAllEmptySelector(t *testing.T) {
+	// GIVEN: cluster
+	cluster := &k8t.Cluster{}
+
+	// THEN: empty selector is rejected without explicit opt-in
+	err := cluster.DeleteAll("v1", "Namespace", "", k8t.PurgeOpts{})
+	if err == nil {
+		t.FailNow()
+	}
+
+	err = cluster.PurgeNamespace("my-namespace", "")
+	if err == nil {
+		t.FailNow()
+	}
+}
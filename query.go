@@ -0,0 +1,232 @@
+package k8t
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Query unstructured object with JSONPath expression, the same syntax
+// kubectl is using for '-o jsonpath'. The braces are optional, so these
+// expressions are equivalent:
+//
+//	{.spec.containers[2].image}
+//	.spec.containers[2].image
+//	spec.containers[2].image
+//
+// If expression matches single value, the value is returned (string,
+// int64, float64, bool, map or slice). If it matches multiple values (e.g.
+// wildcards or filters), they're returned as []interface{}. Missing path is
+// reported as error.
+func Query(obj *unstructured.Unstructured, path string) (interface{}, error) {
+	if obj == nil {
+		return nil, fmt.Errorf("cannot query '%s' on nil object", path)
+	}
+
+	jp, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return queryParsed(jp, obj, path)
+}
+
+// parse kubectl-like relaxed JSONPath, so it can be evaluated multiple times
+func parseJSONPath(path string) (*jsonpath.JSONPath, error) {
+	jp := jsonpath.New("query")
+	err := jp.Parse(relaxedJSONPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid path '%s': %w", path, err)
+	}
+	return jp, nil
+}
+
+// evaluate parsed JSONPath on object. See Query() for more details.
+func queryParsed(jp *jsonpath.JSONPath, obj *unstructured.Unstructured, path string) (interface{}, error) {
+	results, err := jp.FindResults(obj.Object)
+	if err != nil {
+		return nil, fmt.Errorf("path '%s' not found in %s: %w", path, objectRef(obj), err)
+	}
+
+	values := make([]interface{}, 0)
+	for _, result := range results {
+		for _, v := range result {
+			values = append(values, v.Interface())
+		}
+	}
+
+	switch len(values) {
+	case 0:
+		return nil, fmt.Errorf("path '%s' not found in %s", path, objectRef(obj))
+	case 1:
+		return values[0], nil
+	default:
+		return values, nil
+	}
+}
+
+// Query unstructured object with JSONPath expression and convert the result
+// into given type. Numbers are converted between numeric types, multiple
+// values can be returned as slice of given element type:
+//
+//	replicas, err := k8t.QueryAs[int](deployment, ".spec.replicas")
+//	images, err := k8t.QueryAs[[]string](deployment, ".spec.template.spec.containers[*].image")
+func QueryAs[T any](obj *unstructured.Unstructured, path string) (T, error) {
+	var result T
+
+	value, err := Query(obj, path)
+	if err != nil {
+		return result, err
+	}
+
+	converted, err := convertValue(value, reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return result, fmt.Errorf("path '%s' in %s: %w", path, objectRef(obj), err)
+	}
+
+	// null value into interface type is nil interface, not T
+	result, _ = converted.Interface().(T)
+	return result, nil
+}
+
+// Get resource in test namespace and query its field with JSONPath
+// expression. See Query() for more details.
+func (c *Cluster) GetField(apiVersion, kind, name, path string) (interface{}, error) {
+	return c.GetFieldWithOpts(apiVersion, kind, name, path, GetOpts{})
+}
+
+// More verbose version of GetField() function. Use this function if you want
+// to pass own context, or specify the namespace
+func (c *Cluster) GetFieldWithOpts(apiVersion, kind, name, path string, opts GetOpts) (interface{}, error) {
+	res, err := c.GetWithOpts(apiVersion, kind, name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return Query(res, path)
+}
+
+// convert kubectl-like relaxed JSONPath into the form expected by
+// jsonpath package, e.g. 'status.phase' into '{.status.phase}'
+func relaxedJSONPath(path string) string {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(path, "{") {
+		return path
+	}
+
+	if !strings.HasPrefix(path, ".") && !strings.HasPrefix(path, "$") {
+		path = "." + path
+	}
+
+	return "{" + path + "}"
+}
+
+// convert value from unstructured object into given type
+func convertValue(value interface{}, target reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(target), nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(target) {
+		return v, nil
+	}
+
+	// numbers are int64 or float64 in unstructured objects
+	if isNumber(v.Kind()) && isNumber(target.Kind()) {
+		return convertNumber(v, target)
+	}
+
+	// multiple values or lists into typed slice
+	if v.Kind() == reflect.Slice && target.Kind() == reflect.Slice {
+		s := reflect.MakeSlice(target, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, err := convertValue(v.Index(i).Interface(), target.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			s.Index(i).Set(item)
+		}
+		return s, nil
+	}
+
+	// single value into slice of one item
+	if target.Kind() == reflect.Slice {
+		item, err := convertValue(value, target.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		s := reflect.MakeSlice(target, 1, 1)
+		s.Index(0).Set(item)
+		return s, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot convert %T to %s", value, target)
+}
+
+// convert number into given numeric type. Floats are converted into
+// integers only if they're whole numbers, so 1.5 is not truncated into 1.
+// Values which don't fit into target type are reported as error.
+func convertNumber(v reflect.Value, target reflect.Type) (reflect.Value, error) {
+	if isFloat(target.Kind()) {
+		return v.Convert(target), nil
+	}
+
+	zero := reflect.New(target).Elem()
+
+	overflow := false
+	switch {
+	case isFloat(v.Kind()):
+		f := v.Float()
+		if f != math.Trunc(f) {
+			return reflect.Value{}, fmt.Errorf("cannot convert %v to %s, it's not a whole number", f, target)
+		}
+
+		if zero.CanInt() {
+			overflow = f < -math.Exp2(63) || f >= math.Exp2(63) || zero.OverflowInt(int64(f))
+		} else {
+			overflow = f < 0 || f >= math.Exp2(64) || zero.OverflowUint(uint64(f))
+		}
+
+	case v.CanInt():
+		i := v.Int()
+		if zero.CanInt() {
+			overflow = zero.OverflowInt(i)
+		} else {
+			overflow = i < 0 || zero.OverflowUint(uint64(i))
+		}
+
+	default:
+		u := v.Uint()
+		if zero.CanInt() {
+			overflow = u > math.MaxInt64 || zero.OverflowInt(int64(u))
+		} else {
+			overflow = zero.OverflowUint(u)
+		}
+	}
+
+	if overflow {
+		return reflect.Value{}, fmt.Errorf("cannot convert %v to %s, it overflows", v.Interface(), target)
+	}
+
+	return v.Convert(target), nil
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,168 @@
+package k8t
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var queryTestPod = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: my-pod
+spec:
+  containers:
+  - name: first
+    image: busybox:1.35
+  - name: second
+    image: busybox:1.36
+  - name: third
+    image: nginx:1.25
+status:
+  phase: Running
+  conditions:
+  - type: Initialized
+    status: "True"
+  - type: Ready
+    status: "False"
+  containerStatuses:
+  - restartCount: 3
+`
+
+func decodeQueryTestPod(t *testing.T) *unstructured.Unstructured {
+	objs, err := DecodeManifest(queryTestPod)
+	if err != nil {
+		t.FailNow()
+	}
+	return objs[0]
+}
+
+func Test_Query(t *testing.T) {
+	// GIVEN: some pod
+	pod := decodeQueryTestPod(t)
+
+	// WHEN: I query the third container's image
+	image, err := Query(pod, "{.spec.containers[2].image}")
+
+	// THEN: I get the image
+	if err != nil || image != "nginx:1.25" {
+		t.FailNow()
+	}
+
+	// AND: I can use filter without braces
+	ready, err := Query(pod, `status.conditions[?(@.type=="Ready")].status`)
+	if err != nil || ready != "False" {
+		t.FailNow()
+	}
+
+	// AND: multiple values are returned as slice
+	images, err := Query(pod, ".spec.containers[*].image")
+	if err != nil || len(images.([]interface{})) != 3 {
+		t.FailNow()
+	}
+}
+
+func Test_Query_Missing(t *testing.T) {
+	// GIVEN: some pod
+	pod := decodeQueryTestPod(t)
+
+	// WHEN: I query missing field
+	_, err := Query(pod, ".status.podIP")
+
+	// THEN: I get an error
+	if err == nil {
+		t.FailNow()
+	}
+
+	// AND: filter which doesn't match anything ends with error too
+	_, err = Query(pod, `.status.conditions[?(@.type=="Unknown")].status`)
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func Test_QueryAs(t *testing.T) {
+	// GIVEN: some pod
+	pod := decodeQueryTestPod(t)
+
+	// WHEN: I query number as int
+	restarts, err := QueryAs[int](pod, ".status.containerStatuses[0].restartCount")
+
+	// THEN: I get typed value
+	if err != nil || restarts != 3 {
+		t.FailNow()
+	}
+
+	// AND: I can get multiple values as typed slice
+	images, err := QueryAs[[]string](pod, ".spec.containers[*].image")
+	if err != nil || len(images) != 3 || images[1] != "busybox:1.36" {
+		t.FailNow()
+	}
+
+	// AND: incompatible type is reported as error
+	_, err = QueryAs[bool](pod, ".status.phase")
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func Test_QueryAs_Null(t *testing.T) {
+	// GIVEN: object with null field, e.g. converted from typed struct
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":              "my-config",
+			"creationTimestamp": nil,
+		},
+	}}
+
+	// WHEN: I query the null field as interface type
+	value, err := QueryAs[any](obj, ".metadata.creationTimestamp")
+
+	// THEN: I get nil without panic
+	if err != nil || value != nil {
+		t.FailNow()
+	}
+
+	// AND: as concrete type I get zero value
+	str, err := QueryAs[string](obj, ".metadata.creationTimestamp")
+	if err != nil || str != "" {
+		t.FailNow()
+	}
+}
+
+func Test_QueryAs_Numbers(t *testing.T) {
+	// GIVEN: object with whole and fractional floats
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "my-config"},
+		"spec": map[string]interface{}{
+			"whole":    float64(3),
+			"fraction": 1.5,
+			"negative": int64(-1),
+		},
+	}}
+
+	// THEN: whole number is converted into int
+	if whole, err := QueryAs[int](obj, ".spec.whole"); err != nil || whole != 3 {
+		t.FailNow()
+	}
+
+	// AND: fraction is not truncated, but reported as error
+	if _, err := QueryAs[int](obj, ".spec.fraction"); err == nil {
+		t.FailNow()
+	}
+
+	// AND: fraction can be converted into float
+	if fraction, err := QueryAs[float32](obj, ".spec.fraction"); err != nil || fraction != 1.5 {
+		t.FailNow()
+	}
+
+	// AND: negative number doesn't fit into unsigned type
+	if _, err := QueryAs[uint](obj, ".spec.negative"); err == nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,131 @@
+package k8t
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/restmapper"
+)
+
+// Resolve kubectl-style resource type like 'deploy', 'pods', 'Deployment'
+// or 'ingresses.networking.k8s.io' into apiVersion and kind, which can be
+// used with Get(), List() or checkers:
+//
+//	apiVersion, kind, err := cluster.ResolveKind("deploy")
+//	cluster.WaitFor(k8t.ResourceExist(apiVersion, kind, "my-app"))
+//
+// Short names and plurals are resolved via discovery. Categories (e.g.
+// 'all') are expanded into multiple kinds, so they're not accepted here.
+func (c *Cluster) ResolveKind(resource string) (string, string, error) {
+	gvks, err := c.resolveKinds(resource)
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(gvks) != 1 {
+		return "", "", fmt.Errorf("'%s' is resolved into %d kinds", resource, len(gvks))
+	}
+
+	apiVersion, kind := gvks[0].ToAPIVersionAndKind()
+	return apiVersion, kind, nil
+}
+
+// Less verbose version of GetResourceWithOpts.
+func (c *Cluster) GetResource(ref string) (*unstructured.Unstructured, error) {
+	return c.GetResourceWithOpts(ref, GetOpts{})
+}
+
+// Get resource identified by kubectl-style reference 'TYPE/NAME', e.g.
+// 'deploy/my-app' or 'ingresses.networking.k8s.io/my-ingress'. The type
+// is resolved in the same way ResolveKind() does.
+func (c *Cluster) GetResourceWithOpts(ref string, opts GetOpts) (*unstructured.Unstructured, error) {
+	resource, name, found := strings.Cut(ref, "/")
+	if !found || name == "" {
+		return nil, fmt.Errorf("'%s' is not in format TYPE/NAME", ref)
+	}
+
+	apiVersion, kind, err := c.ResolveKind(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetWithOpts(apiVersion, kind, name, opts)
+}
+
+// Less verbose version of ListResourcesWithOpts.
+func (c *Cluster) ListResources(resource, labelSelector string) (*unstructured.UnstructuredList, error) {
+	return c.ListResourcesWithOpts(resource, labelSelector, ListOpts{})
+}
+
+// List resources of kubectl-style resource type, e.g. 'pods', 'deploy' or
+// categories like 'all'. Multiple types can be separated by comma, e.g.
+// 'deploy,svc'. Objects of all resolved kinds are returned in one list.
+func (c *Cluster) ListResourcesWithOpts(resource, labelSelector string, opts ListOpts) (*unstructured.UnstructuredList, error) {
+	gvks := make([]schema.GroupVersionKind, 0)
+	for _, r := range strings.Split(resource, ",") {
+		resolved, err := c.resolveKinds(r)
+		if err != nil {
+			return nil, err
+		}
+		gvks = append(gvks, resolved...)
+	}
+
+	res := &unstructured.UnstructuredList{}
+	res.SetAPIVersion("v1")
+	res.SetKind("List")
+
+	for _, gvk := range gvks {
+		apiVersion, kind := gvk.ToAPIVersionAndKind()
+		list, err := c.ListWithOpts(apiVersion, kind, labelSelector, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", kind, err)
+		}
+		res.Items = append(res.Items, list.Items...)
+	}
+
+	return res, nil
+}
+
+// resolve kubectl-style resource type into kinds. Categories are expanded
+// into multiple kinds.
+func (c *Cluster) resolveKinds(resource string) ([]schema.GroupVersionKind, error) {
+	resource = strings.ToLower(strings.TrimSpace(resource))
+	if resource == "" {
+		return nil, errors.New("resource type is empty")
+	}
+
+	mapper := restmapper.NewShortcutExpander(c.restMapper, c.discoveryClient)
+
+	// categories like 'all'
+	categoryExpander := restmapper.NewDiscoveryCategoryExpander(c.discoveryClient)
+	if groupResources, ok := categoryExpander.Expand(resource); ok {
+		gvks := make([]schema.GroupVersionKind, 0, len(groupResources))
+		for _, gr := range groupResources {
+			gvk, err := mapper.KindFor(gr.WithVersion(""))
+			if err != nil {
+				return nil, err
+			}
+			gvks = append(gvks, gvk)
+		}
+		return gvks, nil
+	}
+
+	// fully specified resource like 'deployments.v1.apps'
+	fullySpecified, groupResource := schema.ParseResourceArg(resource)
+	if fullySpecified != nil {
+		if gvk, err := mapper.KindFor(*fullySpecified); err == nil {
+			return []schema.GroupVersionKind{gvk}, nil
+		}
+	}
+
+	// short names, plurals, singulars and resources with group
+	gvk, err := mapper.KindFor(groupResource.WithVersion(""))
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve resource type '%s': %w", resource, err)
+	}
+
+	return []schema.GroupVersionKind{gvk}, nil
+}
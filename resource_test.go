@@ -0,0 +1,97 @@
+package k8t
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	memory "k8s.io/client-go/discovery/cached"
+	"k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/restmapper"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// cluster with fake discovery, which knows pods, deployments and ingresses
+func newFakeDiscoveryCluster() *Cluster {
+	fakeDiscovery := &fake.FakeDiscovery{Fake: &k8stesting.Fake{}}
+	fakeDiscovery.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", SingularName: "pod", Kind: "Pod", Namespaced: true, ShortNames: []string{"po"}, Categories: []string{"all"}},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", SingularName: "deployment", Kind: "Deployment", Namespaced: true, ShortNames: []string{"deploy"}, Categories: []string{"all"}},
+			},
+		},
+		{
+			GroupVersion: "networking.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "ingresses", SingularName: "ingress", Kind: "Ingress", Namespaced: true, ShortNames: []string{"ing"}},
+			},
+		},
+	}
+
+	cachedDiscovery := memory.NewMemCacheClient(fakeDiscovery)
+	return &Cluster{
+		discoveryClient: cachedDiscovery,
+		restMapper:      restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery),
+	}
+}
+
+func Test_ResolveKind(t *testing.T) {
+	// GIVEN: cluster with some resources
+	cluster := newFakeDiscoveryCluster()
+
+	// WHEN: I resolve kubectl-style resource types
+	// THEN: I get apiVersion and kind
+	tests := map[string]string{
+		"deploy":                      "apps/v1 Deployment",
+		"deployments":                 "apps/v1 Deployment",
+		"Deployment":                  "apps/v1 Deployment",
+		"po":                          "v1 Pod",
+		"ingresses.networking.k8s.io": "networking.k8s.io/v1 Ingress",
+		"deployments.v1.apps":         "apps/v1 Deployment",
+	}
+
+	for resource, expected := range tests {
+		apiVersion, kind, err := cluster.ResolveKind(resource)
+		if err != nil || apiVersion+" "+kind != expected {
+			t.Fatalf("%s resolved into %s %s (%v)", resource, apiVersion, kind, err)
+		}
+	}
+}
+
+func Test_resolveKinds_Category(t *testing.T) {
+	// GIVEN: cluster with some resources in 'all' category
+	cluster := newFakeDiscoveryCluster()
+
+	// WHEN: I resolve the category
+	gvks, err := cluster.resolveKinds("all")
+
+	// THEN: I get all the kinds in the category
+	if err != nil || len(gvks) != 2 {
+		t.FailNow()
+	}
+
+	// AND: category cannot be resolved into single kind
+	_, _, err = cluster.ResolveKind("all")
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func Test_ResolveKind_Unknown(t *testing.T) {
+	// GIVEN: cluster with some resources
+	cluster := newFakeDiscoveryCluster()
+
+	// WHEN: I resolve unknown resource type
+	_, _, err := cluster.ResolveKind("foo")
+
+	// THEN: I get an error
+	if err == nil {
+		t.FailNow()
+	}
+}
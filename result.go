@@ -0,0 +1,67 @@
+package k8t
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// describes what happened with object during apply
+type ApplyOutcome string
+
+const (
+	// object didn't exist and it was created
+	Created ApplyOutcome = "created"
+
+	// object existed and it was changed by apply
+	Configured ApplyOutcome = "configured"
+
+	// object existed and apply didn't change anything
+	Unchanged ApplyOutcome = "unchanged"
+)
+
+// live object returned by server after apply, together with the outcome
+type AppliedObject struct {
+	// the live object with UID, resourceVersion and defaulted fields
+	Object *unstructured.Unstructured
+
+	Outcome ApplyOutcome
+}
+
+// result of ApplyWithOpts() and other verbose apply functions. It contains
+// all successfully applied objects in order they were applied.
+type ApplyResult struct {
+	Objects []AppliedObject
+}
+
+// returns live object of given apiVersion, kind, namespace and name, or nil
+// if the object is not in the result. Use empty namespace for cluster-wide
+// objects.
+func (r ApplyResult) Find(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	for _, o := range r.Objects {
+		if o.Object.GetAPIVersion() == apiVersion && o.Object.GetKind() == kind &&
+			o.Object.GetNamespace() == namespace && o.Object.GetName() == name {
+			return o.Object
+		}
+	}
+	return nil
+}
+
+// returns all live objects from the result
+func (r ApplyResult) Unstructured() []*unstructured.Unstructured {
+	objs := make([]*unstructured.Unstructured, len(r.Objects))
+	for i, o := range r.Objects {
+		objs[i] = o.Object
+	}
+	return objs
+}
+
+// print result in the same way as kubectl, e.g. 'deployment/my-app created'
+func (r ApplyResult) String() string {
+	var sb strings.Builder
+	for _, o := range r.Objects {
+		fmt.Fprintf(&sb, "%s/%s %s\n", strings.ToLower(o.Object.GetKind()), o.Object.GetName(), o.Outcome)
+	}
+	return sb.String()
+}
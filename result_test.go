@@ -0,0 +1,72 @@
+package k8t
+
+import (
+	"testing"
+)
+
+func Test_ApplyResult(t *testing.T) {
+	// GIVEN: result of apply with objects in different namespaces
+	result := ApplyResult{
+		Objects: []AppliedObject{
+			{Object: newTestObject("v1", "ConfigMap", "config"), Outcome: Created},
+			{Object: newTestObject("apps/v1", "Deployment", "app"), Outcome: Unchanged},
+			{Object: newTestObject("apps/v1", "Deployment", "app"), Outcome: Created},
+		},
+	}
+	result.Objects[1].Object.SetNamespace("first")
+	result.Objects[2].Object.SetNamespace("second")
+
+	// WHEN: I find the deployment in second namespace
+	deployment := result.Find("apps/v1", "Deployment", "second", "app")
+
+	// THEN: I get the right live object
+	if deployment == nil || deployment.GetNamespace() != "second" {
+		t.FailNow()
+	}
+
+	// AND: object of different kind or group is nil
+	if result.Find("v1", "Service", "second", "app") != nil {
+		t.FailNow()
+	}
+
+	if result.Find("extensions/v1beta1", "Deployment", "second", "app") != nil {
+		t.FailNow()
+	}
+
+	// AND: result is printed like kubectl does
+	if result.String() != "configmap/config created\ndeployment/app unchanged\ndeployment/app created\n" {
+		t.FailNow()
+	}
+}
+
+func Test_applyOutcome(t *testing.T) {
+	// GIVEN: live object returned by apply
+	live := newTestObject("v1", "ConfigMap", "config")
+	live.SetResourceVersion("2")
+
+	// THEN: object is created when it didn't exist before
+	if applyOutcome(nil, live) != Created {
+		t.FailNow()
+	}
+
+	// AND: object is configured when resourceVersion is changed
+	existing := live.DeepCopy()
+	existing.SetResourceVersion("1")
+	if applyOutcome(existing, live) != Configured {
+		t.FailNow()
+	}
+
+	// AND: object is unchanged when resourceVersion stays
+	if applyOutcome(live.DeepCopy(), live) != Unchanged {
+		t.FailNow()
+	}
+
+	// WHEN: dry-run changes the content, but not the resourceVersion
+	dryRun := live.DeepCopy()
+	dryRun.SetLabels(map[string]string{"app": "new"})
+
+	// THEN: object is configured
+	if applyOutcome(live, dryRun) != Configured {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,102 @@
+package k8t
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+// scheme used for resolving apiVersion and kind of typed Go objects. It
+// contains all the built-in Kubernetes types known to client-go. Your own
+// types (e.g. CRDs) can be registered by AddToScheme().
+var scheme = newScheme()
+
+// Register your own Go types (typically CRD types) into scheme used by K8T,
+// so they can be used with GetAs(), ListAs(), ApplyObject() etc. The function
+// accepts AddToScheme function generated for your API package:
+//
+//	k8t.AddToScheme(myapiv1.AddToScheme)
+//
+// Call it before tests are running (e.g. in TestMain or init), the scheme
+// is not safe for concurrent registration and use.
+func AddToScheme(addToScheme func(*runtime.Scheme) error) error {
+	return addToScheme(scheme)
+}
+
+func newScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// returns GroupVersionKind of given object. If object has apiVersion and
+// kind set, they're used, otherwise they're resolved from the scheme.
+func gvkForObject(obj runtime.Object) (schema.GroupVersionKind, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Kind != "" && gvk.Version != "" {
+		return gvk, nil
+	}
+
+	if _, ok := obj.(*unstructured.Unstructured); ok {
+		return schema.GroupVersionKind{}, fmt.Errorf("unstructured object has no apiVersion or kind")
+	}
+
+	gvks, _, err := scheme.ObjectKinds(obj)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+
+	return gvks[0], nil
+}
+
+// convert typed or unstructured object into unstructured with
+// apiVersion and kind set
+func toUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	gvk, err := gvkForObject(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u, nil
+	}
+
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &unstructured.Unstructured{Object: data}
+	u.SetGroupVersionKind(gvk)
+	return u, nil
+}
+
+// returns copy of object without fields maintained by server. The typed
+// objects are updated with live state after apply, so they carry
+// managedFields and resourceVersion. Server rejects apply with
+// managedFields and resourceVersion would be a stale-write precondition
+// for the next apply of the same object.
+func stripForApply(u *unstructured.Unstructured) *unstructured.Unstructured {
+	clean := u.DeepCopy()
+	unstructured.RemoveNestedField(clean.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(clean.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(clean.Object, "metadata", "uid")
+	unstructured.RemoveNestedField(clean.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(clean.Object, "status")
+	return clean
+}
+
+// decode unstructured object into given typed or unstructured object
+func fromUnstructured(u *unstructured.Unstructured, obj runtime.Object) error {
+	if target, ok := obj.(*unstructured.Unstructured); ok {
+		target.Object = u.Object
+		return nil
+	}
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj)
+}
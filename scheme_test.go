@@ -0,0 +1,121 @@
+package k8t
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func Test_toUnstructured(t *testing.T) {
+	// GIVEN: typed pod without apiVersion and kind
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pod"},
+	}
+
+	// WHEN: I convert it to unstructured
+	u, err := toUnstructured(pod)
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: apiVersion and kind are resolved from scheme
+	if u.GetAPIVersion() != "v1" || u.GetKind() != "Pod" || u.GetName() != "my-pod" {
+		t.FailNow()
+	}
+}
+
+func Test_stripForApply(t *testing.T) {
+	// GIVEN: pod updated with live state from server
+	u, err := toUnstructured(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "my-pod",
+			UID:               "1234",
+			ResourceVersion:   "42",
+			CreationTimestamp: metav1.Now(),
+			ManagedFields:     []metav1.ManagedFieldsEntry{{Manager: "k8t"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I prepare it for next apply
+	clean := stripForApply(u)
+
+	// THEN: fields maintained by server are removed
+	if clean.GetManagedFields() != nil || clean.GetResourceVersion() != "" || clean.GetUID() != "" {
+		t.FailNow()
+	}
+
+	if _, found := clean.Object["status"]; found {
+		t.FailNow()
+	}
+
+	// AND: the original object is not modified
+	if u.GetResourceVersion() != "42" || clean.GetName() != "my-pod" {
+		t.FailNow()
+	}
+}
+
+func Test_fromUnstructured(t *testing.T) {
+	// GIVEN: unstructured pod
+	u, err := toUnstructured(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pod", UID: "1234"},
+	})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I decode it into typed pod
+	pod := &corev1.Pod{}
+	err = fromUnstructured(u, pod)
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: the pod is filled
+	if pod.Name != "my-pod" || pod.UID != "1234" || pod.Kind != "Pod" {
+		t.FailNow()
+	}
+}
+
+// custom resource type used for testing AddToScheme
+type testGreeting struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Message           string `json:"message,omitempty"`
+}
+
+func (g *testGreeting) DeepCopyObject() runtime.Object {
+	c := *g
+	g.ObjectMeta.DeepCopyInto(&c.ObjectMeta)
+	return &c
+}
+
+func Test_AddToScheme(t *testing.T) {
+	// GIVEN: custom Go type for CRD
+	gv := schema.GroupVersion{Group: "k8t.sn3d.com", Version: "v1"}
+
+	// WHEN: I register the type
+	err := AddToScheme(func(s *runtime.Scheme) error {
+		s.AddKnownTypeWithName(gv.WithKind("Greeting"), &testGreeting{})
+		return nil
+	})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: apiVersion and kind are resolved for the type
+	gvk, err := gvkForObject(&testGreeting{})
+	if err != nil {
+		t.FailNow()
+	}
+
+	if gvk.GroupVersion() != gv || gvk.Kind != "Greeting" {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,330 @@
+package k8t
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// computed status of the object, similar to kstatus
+type Status string
+
+const (
+	// object is fully reconciled, the actual state matches the desired state
+	StatusCurrent Status = "Current"
+
+	// object is still reconciling, e.g. deployment is rolling out
+	StatusInProgress Status = "InProgress"
+
+	// object failed and it's not expected to reconcile without change,
+	// e.g. job exceeded backoff limit
+	StatusFailed Status = "Failed"
+
+	// object is being deleted
+	StatusTerminating Status = "Terminating"
+)
+
+// Compute status of any object. Well-known built-in kinds (workloads, pods,
+// jobs, PVCs, services, namespaces and CRDs) use the same rules as their
+// checkers. Other kinds, usually custom resources, follow the standard
+// 'status.conditions' convention:
+//   - 'metadata.generation' newer than 'status.observedGeneration' is InProgress
+//   - 'Stalled' condition with 'True' is Failed
+//   - 'Reconciling' condition with 'True' is InProgress
+//   - 'Ready' or 'Available' condition with other than 'True' is InProgress
+//   - otherwise the object is Current
+//
+// The function returns also human readable message explaining the status.
+func ComputeStatus(obj *unstructured.Unstructured) (Status, string, error) {
+	if obj.GetDeletionTimestamp() != nil {
+		return StatusTerminating, "object is being deleted", nil
+	}
+
+	gk := obj.GroupVersionKind().GroupKind()
+	if compute, ok := builtinStatus[gk]; ok {
+		return compute(obj)
+	}
+
+	return genericStatus(obj)
+}
+
+// check if given object is Current. See ComputeStatus() for the rules. If
+// the object is Failed, the checker returns error wrapping ErrTerminal,
+// because it's not expected to become Current. The object is looked up in
+// cluster's test namespace.
+func IsCurrent(apiVersion, kind, name string) Checker {
+	return isCurrent(apiVersion, kind, "", name, nil)
+}
+
+// simple version of WaitForAllCurrentWithOpts() with default timeout and
+// interval
+func (c *Cluster) WaitForAllCurrent(objs []*unstructured.Unstructured) error {
+	return c.WaitForAllCurrentWithOpts(objs, WaitForOpts{})
+}
+
+// wait until all given objects are Current, e.g. everything from apply:
+//
+//	result, err := cluster.ApplyWithOpts(manifest, k8t.ApplyOpts{})
+//	err = cluster.WaitForAllCurrent(result.Unstructured())
+//
+// Objects are looked up in their namespace, or in cluster's test namespace
+// if they don't have any. The function fails immediately when some object
+// is Failed. On timeout, the error contains the object which is still not
+// Current and its status.
+func (c *Cluster) WaitForAllCurrentWithOpts(objs []*unstructured.Unstructured, opts WaitForOpts) error {
+	pending := ""
+	checks := make([]Checker, 0, len(objs))
+	for _, obj := range objs {
+		checks = append(checks, isCurrent(obj.GetAPIVersion(), obj.GetKind(), obj.GetNamespace(), obj.GetName(), &pending))
+	}
+
+	err := c.WaitForWithOpts(All(checks...), opts)
+	if err != nil && pending != "" {
+		return fmt.Errorf("%s: %w", pending, err)
+	}
+	return err
+}
+
+// returns checker for IsCurrent. If pending is set, the status of the
+// object which is not Current is stored there
+func isCurrent(apiVersion, kind, namespace, name string, pending *string) Checker {
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		ref := kind + "/" + name
+
+		obj, err := c.GetWithOpts(apiVersion, kind, name, GetOpts{Context: ctx, Namespace: namespace})
+		if apierrors.IsNotFound(err) {
+			if pending != nil {
+				*pending = ref + " is not found"
+			}
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		status, message, err := ComputeStatus(obj)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", ref, err)
+		}
+
+		switch status {
+		case StatusCurrent:
+			return true, nil
+		case StatusFailed:
+			return false, Terminalf("%s is %s: %s", ref, status, message)
+		default:
+			if pending != nil {
+				*pending = fmt.Sprintf("%s is %s: %s", ref, status, message)
+			}
+			return false, nil
+		}
+	}
+}
+
+// rules for well-known built-in kinds
+var builtinStatus = map[schema.GroupKind]func(*unstructured.Unstructured) (Status, string, error){
+	{Group: "apps", Kind: "Deployment"}:                               deploymentStatus,
+	{Group: "apps", Kind: "StatefulSet"}:                              statefulSetStatus,
+	{Group: "apps", Kind: "DaemonSet"}:                                daemonSetStatus,
+	{Group: "apps", Kind: "ReplicaSet"}:                               replicaSetStatus,
+	{Group: "batch", Kind: "Job"}:                                     jobStatus,
+	{Group: "", Kind: "Pod"}:                                          podStatus,
+	{Group: "", Kind: "PersistentVolumeClaim"}:                        pvcStatus,
+	{Group: "", Kind: "Service"}:                                      serviceStatus,
+	{Group: "", Kind: "Namespace"}:                                    namespaceStatus,
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: crdStatus,
+}
+
+func deploymentStatus(obj *unstructured.Unstructured) (Status, string, error) {
+	d := &appsv1.Deployment{}
+	if err := fromUnstructured(obj, d); err != nil {
+		return "", "", err
+	}
+
+	ok, err := deploymentIsAvailable(d)
+	switch {
+	case err != nil:
+		return StatusFailed, err.Error(), nil
+	case ok:
+		return StatusCurrent, "deployment is available", nil
+	default:
+		return StatusInProgress, fmt.Sprintf("%d of %d updated replicas are available", d.Status.AvailableReplicas, d.Status.UpdatedReplicas), nil
+	}
+}
+
+func statefulSetStatus(obj *unstructured.Unstructured) (Status, string, error) {
+	sts := &appsv1.StatefulSet{}
+	if err := fromUnstructured(obj, sts); err != nil {
+		return "", "", err
+	}
+
+	ok, err := statefulSetIsReady(sts)
+	switch {
+	case err != nil:
+		return StatusFailed, err.Error(), nil
+	case ok:
+		return StatusCurrent, "statefulset is ready", nil
+	default:
+		return StatusInProgress, fmt.Sprintf("%d replicas are ready", sts.Status.ReadyReplicas), nil
+	}
+}
+
+func daemonSetStatus(obj *unstructured.Unstructured) (Status, string, error) {
+	ds := &appsv1.DaemonSet{}
+	if err := fromUnstructured(obj, ds); err != nil {
+		return "", "", err
+	}
+
+	ok, err := daemonSetIsReady(ds)
+	switch {
+	case err != nil:
+		return StatusFailed, err.Error(), nil
+	case ok:
+		return StatusCurrent, "daemonset is ready", nil
+	default:
+		return StatusInProgress, fmt.Sprintf("%d of %d pods are available", ds.Status.NumberAvailable, ds.Status.DesiredNumberScheduled), nil
+	}
+}
+
+func replicaSetStatus(obj *unstructured.Unstructured) (Status, string, error) {
+	rs := &appsv1.ReplicaSet{}
+	if err := fromUnstructured(obj, rs); err != nil {
+		return "", "", err
+	}
+
+	if rs.Generation > rs.Status.ObservedGeneration {
+		return StatusInProgress, "new generation is not observed yet", nil
+	}
+
+	replicas := int32(1)
+	if rs.Spec.Replicas != nil {
+		replicas = *rs.Spec.Replicas
+	}
+
+	if rs.Status.AvailableReplicas < replicas {
+		return StatusInProgress, fmt.Sprintf("%d of %d replicas are available", rs.Status.AvailableReplicas, replicas), nil
+	}
+	return StatusCurrent, "replicaset is available", nil
+}
+
+func jobStatus(obj *unstructured.Unstructured) (Status, string, error) {
+	job := &batchv1.Job{}
+	if err := fromUnstructured(obj, job); err != nil {
+		return "", "", err
+	}
+
+	switch {
+	case jobHasCondition(job, batchv1.JobFailed):
+		cond := jobCondition(job, batchv1.JobFailed)
+		return StatusFailed, fmt.Sprintf("job failed: %s: %s", cond.Reason, cond.Message), nil
+	case jobHasCondition(job, batchv1.JobComplete):
+		return StatusCurrent, "job completed", nil
+	default:
+		return StatusInProgress, fmt.Sprintf("%d pods are active", job.Status.Active), nil
+	}
+}
+
+func podStatus(obj *unstructured.Unstructured) (Status, string, error) {
+	pod := &corev1.Pod{}
+	if err := fromUnstructured(obj, pod); err != nil {
+		return "", "", err
+	}
+
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return StatusCurrent, "pod succeeded", nil
+	case corev1.PodFailed:
+		return StatusFailed, "pod failed", nil
+	}
+
+	ok, err := podIsReady(pod)
+	switch {
+	case err != nil:
+		return StatusFailed, err.Error(), nil
+	case ok:
+		return StatusCurrent, "pod is ready", nil
+	default:
+		return StatusInProgress, fmt.Sprintf("pod is %s and not ready", pod.Status.Phase), nil
+	}
+}
+
+func pvcStatus(obj *unstructured.Unstructured) (Status, string, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := fromUnstructured(obj, pvc); err != nil {
+		return "", "", err
+	}
+
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return StatusInProgress, "claim is not bound", nil
+	}
+	return StatusCurrent, "claim is bound", nil
+}
+
+func serviceStatus(obj *unstructured.Unstructured) (Status, string, error) {
+	svc := &corev1.Service{}
+	if err := fromUnstructured(obj, svc); err != nil {
+		return "", "", err
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer && len(svc.Status.LoadBalancer.Ingress) == 0 {
+		return StatusInProgress, "load balancer is not provisioned", nil
+	}
+	return StatusCurrent, "service is ready", nil
+}
+
+func namespaceStatus(obj *unstructured.Unstructured) (Status, string, error) {
+	ns := &corev1.Namespace{}
+	if err := fromUnstructured(obj, ns); err != nil {
+		return "", "", err
+	}
+
+	if ns.Status.Phase != corev1.NamespaceActive {
+		return StatusInProgress, "namespace is not active", nil
+	}
+	return StatusCurrent, "namespace is active", nil
+}
+
+func crdStatus(obj *unstructured.Unstructured) (Status, string, error) {
+	if cond := findCondition(obj, "NamesAccepted"); cond != nil && cond.status == "False" {
+		return StatusFailed, cond.message, nil
+	}
+
+	if cond := findCondition(obj, "Established"); cond == nil || cond.status != "True" {
+		return StatusInProgress, "CRD is not established", nil
+	}
+	return StatusCurrent, "CRD is established", nil
+}
+
+// status of objects which follow the 'status.conditions' convention
+func genericStatus(obj *unstructured.Unstructured) (Status, string, error) {
+	observed, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err != nil {
+		return "", "", err
+	}
+
+	if found && obj.GetGeneration() > observed {
+		return StatusInProgress, fmt.Sprintf("generation %d is not observed yet", obj.GetGeneration()), nil
+	}
+
+	if cond := findCondition(obj, "Stalled"); cond != nil && cond.status == "True" {
+		return StatusFailed, cond.describe(), nil
+	}
+
+	if cond := findCondition(obj, "Reconciling"); cond != nil && cond.status == "True" {
+		return StatusInProgress, cond.describe(), nil
+	}
+
+	for _, condType := range []string{"Ready", "Available"} {
+		if cond := findCondition(obj, condType); cond != nil && cond.status != "True" {
+			return StatusInProgress, cond.describe(), nil
+		}
+	}
+
+	return StatusCurrent, "object is current", nil
+}
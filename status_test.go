@@ -0,0 +1,120 @@
+package k8t
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_ComputeStatus(t *testing.T) {
+	// GIVEN: custom resource with Ready condition which is not true yet
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "k8t.sn3d.com/v1",
+		"kind":       "Greeting",
+		"metadata":   map[string]interface{}{"name": "hello", "generation": int64(2)},
+		"status": map[string]interface{}{
+			"observedGeneration": int64(2),
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "reason": "Waiting"},
+			},
+		},
+	}}
+
+	// THEN: it's in progress
+	if status, _, err := ComputeStatus(obj); status != StatusInProgress || err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: the object becomes ready
+	unstructured.SetNestedSlice(obj.Object, []interface{}{
+		map[string]interface{}{"type": "Ready", "status": "True"},
+	}, "status", "conditions")
+
+	// THEN: it's current
+	if status, _, _ := ComputeStatus(obj); status != StatusCurrent {
+		t.FailNow()
+	}
+
+	// WHEN: new generation is not observed yet
+	obj.SetGeneration(3)
+
+	// THEN: the stale Ready condition doesn't count
+	if status, _, _ := ComputeStatus(obj); status != StatusInProgress {
+		t.FailNow()
+	}
+
+	// WHEN: controller is stalled
+	unstructured.SetNestedField(obj.Object, int64(3), "status", "observedGeneration")
+	unstructured.SetNestedSlice(obj.Object, []interface{}{
+		map[string]interface{}{"type": "Stalled", "status": "True", "message": "invalid spec"},
+	}, "status", "conditions")
+
+	// THEN: it's failed
+	if status, msg, _ := ComputeStatus(obj); status != StatusFailed || msg != "Stalled is True: invalid spec" {
+		t.FailNow()
+	}
+
+	// WHEN: the object is being deleted
+	now := metav1.Now()
+	obj.SetDeletionTimestamp(&now)
+
+	// THEN: it's terminating
+	if status, _, _ := ComputeStatus(obj); status != StatusTerminating {
+		t.FailNow()
+	}
+}
+
+func Test_ComputeStatusBuiltin(t *testing.T) {
+	// GIVEN: deployment which is rolling out
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "app", "generation": int64(1)},
+		"spec":       map[string]interface{}{"replicas": int64(2)},
+		"status": map[string]interface{}{
+			"observedGeneration": int64(1),
+			"replicas":           int64(2),
+			"updatedReplicas":    int64(2),
+			"availableReplicas":  int64(1),
+		},
+	}}
+
+	// THEN: it's in progress
+	if status, _, err := ComputeStatus(obj); status != StatusInProgress || err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: all replicas are available
+	unstructured.SetNestedField(obj.Object, int64(2), "status", "availableReplicas")
+
+	// THEN: it's current
+	if status, _, _ := ComputeStatus(obj); status != StatusCurrent {
+		t.FailNow()
+	}
+
+	// GIVEN: failed job
+	job := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata":   map[string]interface{}{"name": "job"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Failed", "status": "True"},
+			},
+		},
+	}}
+
+	// THEN: it's failed
+	if status, _, _ := ComputeStatus(job); status != StatusFailed {
+		t.FailNow()
+	}
+
+	// GIVEN: configmap without status
+	cm := newTestObject("v1", "ConfigMap", "config")
+
+	// THEN: it's current
+	if status, _, _ := ComputeStatus(cm); status != StatusCurrent {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,150 @@
+package k8t
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Optional options for status and scale functions
+type SubresourceOpts struct {
+	// here you can pass your context. It it's not set, the default
+	// context.Background() will be used
+	Context context.Context
+
+	// namespace where is the resource. If it's not set, the namespace from
+	// object's metadata or the cluster's testNamespace will be used. Objects
+	// with different namespace in metadata are rejected
+	Namespace string
+}
+
+// Update the '/status' subresource of given typed or unstructured object.
+// It's handy when you test controller in isolation and you need to pretend
+// other components updated the status (e.g. mark Deployment as available).
+//
+// If the object has no resourceVersion, the current one is used, so the
+// update overrides any concurrent change. After successful update, the given
+// object is updated with the live object from server.
+func (c *Cluster) UpdateStatus(obj runtime.Object) error {
+	return c.UpdateStatusWithOpts(obj, SubresourceOpts{})
+}
+
+// More verbose version of UpdateStatus() function. Use this function if you
+// want to pass own context, or specify the namespace
+func (c *Cluster) UpdateStatusWithOpts(obj runtime.Object, opts SubresourceOpts) error {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return err
+	}
+
+	namespace, err := objectNamespace(u, opts.Namespace, c.testNamespace)
+	if err != nil {
+		return err
+	}
+
+	dr, err := c.resourceFor(u.GetAPIVersion(), u.GetKind(), namespace)
+	if err != nil {
+		return err
+	}
+
+	if u.GetResourceVersion() == "" {
+		current, err := dr.Get(ctx, u.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		// don't modify the object given by caller
+		u = u.DeepCopy()
+		u.SetResourceVersion(current.GetResourceVersion())
+	}
+
+	live, err := dr.UpdateStatus(ctx, u, metav1.UpdateOptions{
+		FieldManager: "k8t",
+	})
+	if err != nil {
+		return err
+	}
+
+	return fromUnstructured(live, obj)
+}
+
+// Patch the '/status' subresource of given resource in test namespace.
+// See Patch() for more details about patch.
+func (c *Cluster) PatchStatus(apiVersion, kind, name string, patchType types.PatchType, patch string) (*unstructured.Unstructured, error) {
+	return c.PatchStatusWithOpts(apiVersion, kind, name, patchType, patch, PatchOpts{})
+}
+
+// More verbose version of PatchStatus() function. The subresource in
+// options is always set to 'status'.
+func (c *Cluster) PatchStatusWithOpts(apiVersion, kind, name string, patchType types.PatchType, patch string, opts PatchOpts) (*unstructured.Unstructured, error) {
+	opts.Subresource = "status"
+	return c.PatchWithOpts(apiVersion, kind, name, patchType, patch, opts)
+}
+
+// Returns the '/scale' subresource of given resource in test namespace.
+// It works for built-in workloads and CRDs which enable scale subresource.
+func (c *Cluster) GetScale(apiVersion, kind, name string) (*autoscalingv1.Scale, error) {
+	return c.GetScaleWithOpts(apiVersion, kind, name, SubresourceOpts{})
+}
+
+// More verbose version of GetScale() function. Use this function if you
+// want to pass own context, or specify the namespace
+func (c *Cluster) GetScaleWithOpts(apiVersion, kind, name string, opts SubresourceOpts) (*autoscalingv1.Scale, error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = c.testNamespace
+	}
+
+	dr, err := c.resourceFor(apiVersion, kind, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := dr.Get(ctx, name, metav1.GetOptions{}, "scale")
+	if err != nil {
+		return nil, err
+	}
+
+	scale := &autoscalingv1.Scale{}
+	err = fromUnstructured(res, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	return scale, nil
+}
+
+// Set number of replicas via '/scale' subresource of given resource in
+// test namespace, the same way as 'kubectl scale' does.
+func (c *Cluster) Scale(apiVersion, kind, name string, replicas int32) error {
+	return c.ScaleWithOpts(apiVersion, kind, name, replicas, SubresourceOpts{})
+}
+
+// More verbose version of Scale() function. Use this function if you
+// want to pass own context, or specify the namespace
+func (c *Cluster) ScaleWithOpts(apiVersion, kind, name string, replicas int32, opts SubresourceOpts) error {
+	patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)
+
+	_, err := c.PatchWithOpts(apiVersion, kind, name, MergePatch, patch, PatchOpts{
+		Context:     opts.Context,
+		Namespace:   opts.Namespace,
+		Subresource: "scale",
+	})
+
+	return err
+}
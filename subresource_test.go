@@ -0,0 +1,72 @@
+package k8t_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sn3d/k8t"
+)
+
+func Test_Scale(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster with deployment
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	err = cluster.ApplyFile("testdata/multi-doc.yaml")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I scale the deployment
+	err = cluster.Scale("apps/v1", "Deployment", "multi-doc", 2)
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: the scale subresource has desired replicas
+	scale, err := cluster.GetScale("apps/v1", "Deployment", "multi-doc")
+	if err != nil {
+		t.FailNow()
+	}
+
+	if scale.Spec.Replicas != 2 {
+		t.FailNow()
+	}
+}
+
+func Test_PatchStatus(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster with deployment
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	err = cluster.ApplyFile("testdata/multi-doc.yaml")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I pretend the controller updated the status
+	res, err := cluster.PatchStatus("apps/v1", "Deployment", "multi-doc", k8t.MergePatch, `
+status:
+  observedGeneration: 100
+`)
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: the status is updated
+	if res.Object["status"].(map[string]interface{})["observedGeneration"] != int64(100) {
+		t.FailNow()
+	}
+}
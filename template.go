@@ -0,0 +1,38 @@
+package k8t
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// render manifest template with given data. The template is using Go's
+// text/template syntax with sprig functions (the same what helm offers).
+// The name is used in error messages, so the error points to the template
+// and line where it occurred, e.g. 'template: deployment.yaml:12:5: ...'
+//
+// Missing keys are rendered as empty values, the same way as helm does, so
+// the 'default' function works for them. If the value is required, use
+// sprig's 'fail' function, e.g. '{{ if not .Name }}{{ fail "name is required" }}{{ end }}'.
+func renderTemplate(name, tmpl string, data any) (string, error) {
+	t, err := template.New(name).
+		Funcs(sprig.TxtFuncMap()).
+		Option("missingkey=zero").
+		Parse(tmpl)
+
+	if err != nil {
+		return "", fmt.Errorf("cannot parse manifest template: %w", err)
+	}
+
+	var out bytes.Buffer
+	err = t.Execute(&out, data)
+	if err != nil {
+		return "", fmt.Errorf("cannot render manifest template: %w", err)
+	}
+
+	// missing keys in map[string]any are rendered as '<no value>'
+	return strings.ReplaceAll(out.String(), "<no value>", ""), nil
+}
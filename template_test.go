@@ -0,0 +1,68 @@
+package k8t
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_renderTemplate(t *testing.T) {
+	// GIVEN: manifest template with values and sprig functions
+	tmpl := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Name | lower }}
+spec:
+  replicas: {{ .Replicas | default 1 }}
+`
+
+	// WHEN: I render the template
+	manifest, err := renderTemplate("deployment.yaml", tmpl, map[string]any{
+		"Name":     "My-App",
+		"Replicas": 3,
+	})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: the values are rendered into manifest
+	if !strings.Contains(manifest, "name: my-app") || !strings.Contains(manifest, "replicas: 3") {
+		t.FailNow()
+	}
+}
+
+func Test_renderTemplate_MissingKey(t *testing.T) {
+	// GIVEN: manifest template with default for missing value
+	tmpl := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app
+  labels:
+    team: "{{ .Team }}"
+spec:
+  replicas: {{ .Replicas | default 1 }}
+`
+
+	// WHEN: I render the template without the values
+	manifest, err := renderTemplate("deployment.yaml", tmpl, map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: the default value is used and missing value is empty
+	if !strings.Contains(manifest, "replicas: 1") || !strings.Contains(manifest, `team: ""`) {
+		t.FailNow()
+	}
+}
+
+func Test_renderTemplate_Error(t *testing.T) {
+	// GIVEN: template where the 4th line requires missing value
+	tmpl := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ if not .Missing }}{{ fail \"name is required\" }}{{ end }}\n"
+
+	// WHEN: I render the template
+	_, err := renderTemplate("configmap.yaml", tmpl, map[string]any{})
+
+	// THEN: the error points to the template line
+	if err == nil || !strings.Contains(err.Error(), "configmap.yaml:4") {
+		t.FailNow()
+	}
+}
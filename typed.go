@@ -0,0 +1,68 @@
+package k8t
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Get resource as typed Go object. The apiVersion and kind are resolved
+// from the Go type, so you need only the name:
+//
+//	pod, err := k8t.GetAs[corev1.Pod](cluster, "my-pod", k8t.GetOpts{})
+//
+// Built-in types are known, your own types (e.g. CRDs) must be registered
+// by AddToScheme().
+func GetAs[T any, PT interface {
+	*T
+	runtime.Object
+}](c *Cluster, name string, opts GetOpts) (PT, error) {
+	obj := PT(new(T))
+
+	gvk, err := gvkForObject(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.GetWithOpts(gvk.GroupVersion().String(), gvk.Kind, name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fromUnstructured(res, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// List resources matching the label selector as typed Go objects. The
+// apiVersion and kind are resolved from the Go type:
+//
+//	pods, err := k8t.ListAs[corev1.Pod](cluster, "app=my-app", k8t.ListOpts{})
+//
+// Built-in types are known, your own types (e.g. CRDs) must be registered
+// by AddToScheme().
+func ListAs[T any, PT interface {
+	*T
+	runtime.Object
+}](c *Cluster, labelSelector string, opts ListOpts) ([]T, error) {
+	gvk, err := gvkForObject(PT(new(T)))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.ListWithOpts(gvk.GroupVersion().String(), gvk.Kind, labelSelector, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]T, len(res.Items))
+	for i := range res.Items {
+		err = fromUnstructured(&res.Items[i], PT(&items[i]))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,73 @@
+package k8t_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sn3d/k8t"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_GetAs(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster with deployed service
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	err = cluster.ApplyFile("testdata/simple-service.yaml")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I get the service as typed object
+	svc, err := k8t.GetAs[corev1.Service](cluster, "echo-service", k8t.GetOpts{})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: the fields are accessible without digging in maps
+	if svc.Spec.Ports[0].TargetPort.IntValue() != 5678 {
+		t.FailNow()
+	}
+}
+
+func Test_ListAs(t *testing.T) {
+	if os.Getenv("KUBECONFIG") == "" {
+		t.Skip("No KUBECONFIG defined")
+	}
+
+	// GIVEN: running kind cluster with deployment
+	cluster, err := k8t.NewFromEnvironment()
+	if err != nil {
+		t.FailNow()
+	}
+
+	err = cluster.ApplyFile("testdata/list-test.yaml")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: I list deployments as typed objects
+	deployments, err := k8t.ListAs[appsv1.Deployment](cluster, "", k8t.ListOpts{})
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: I'll find the deployment with 3 replicas
+	found := false
+	for _, d := range deployments {
+		if d.Name == "list-test" && *d.Spec.Replicas == 3 {
+			found = true
+		}
+	}
+
+	if !found {
+		t.FailNow()
+	}
+}
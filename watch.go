@@ -0,0 +1,351 @@
+package k8t
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// Optional options for Watch function
+type WatchOpts struct {
+	// here you can pass your context. It it's not set, the default
+	// context.Background() will be used. When the context is cancelled,
+	// the watch is stopped and the channel is closed.
+	Context context.Context
+
+	// namespace where are the resources. If it's not set, the cluster's
+	// testNamespace will be used. This is ignored for cluster-wide resources
+	Namespace string
+
+	// if it's set, resources in all namespaces are watched and Namespace
+	// is ignored
+	AllNamespaces bool
+
+	// field selector, e.g. 'metadata.name=my-pod'
+	FieldSelector string
+
+	// resourceVersion from which the watch starts. If it's not set, the
+	// existing objects are listed first and sent as 'ADDED' events.
+	ResourceVersion string
+}
+
+// change of the watched resource
+type WatchEvent struct {
+	// type of the change, watch.Added, watch.Modified or watch.Deleted. If
+	// the watch cannot continue, the last event is watch.Error.
+	Type watch.EventType
+
+	// the object after change, or the last known state for deleted objects
+	Object *unstructured.Unstructured
+
+	// the error for watch.Error event
+	Err error
+}
+
+// Watch changes of resources of given apiVersion and kind matching the label
+// selector. The function returns channel of events and function which stops
+// the watch. Watch can be also stopped by cancelling the opts.Context:
+//
+//	events, stop, err := cluster.Watch("v1", "Pod", "app=my-app", k8t.WatchOpts{})
+//	defer stop()
+//
+//	for e := range events {
+//	   fmt.Println(e.Type, e.Object.GetName())
+//	}
+//
+// Watch is resumed from last seen resourceVersion when server closes the
+// connection. When the resourceVersion is too old (410 Gone), the objects
+// are listed again and the missed changes are sent as events. Transient
+// errors (timeouts, 5xx, connection errors) are retried with backoff, the
+// watch.Error event is sent only when retries are exhausted.
+func (c *Cluster) Watch(apiVersion, kind, labelSelector string, opts WatchOpts) (<-chan WatchEvent, func(), error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = c.testNamespace
+	}
+
+	if opts.AllNamespaces {
+		namespace = ""
+	}
+
+	dr, err := c.resourceFor(apiVersion, kind, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	w := newWatcher(dr, labelSelector, opts.FieldSelector)
+	err = w.start(ctx, opts.ResourceVersion)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	return w.events, cancel, nil
+}
+
+// how many times in a row are transient errors retried before the watch
+// gives up
+const maxWatchRetries = 5
+
+// watcher keeps the watch running and remember all known objects, so
+// it can compute missed changes after re-list
+type watcher struct {
+	dr            dynamic.ResourceInterface
+	labelSelector string
+	fieldSelector string
+	events        chan WatchEvent
+
+	// delays between reconnects, it's reset when watch receives events
+	backoff wait.Backoff
+
+	// last known state of objects by namespace/name
+	known map[string]*unstructured.Unstructured
+}
+
+func newWatcher(dr dynamic.ResourceInterface, labelSelector, fieldSelector string) *watcher {
+	return &watcher{
+		dr:            dr,
+		labelSelector: labelSelector,
+		fieldSelector: fieldSelector,
+		events:        make(chan WatchEvent),
+		backoff: wait.Backoff{
+			Duration: 500 * time.Millisecond,
+			Factor:   2,
+			Jitter:   0.1,
+			Steps:    10,
+			Cap:      30 * time.Second,
+		},
+		known: make(map[string]*unstructured.Unstructured),
+	}
+}
+
+// start watching in background. If resourceVersion is empty, the initial
+// list is done synchronously, so the caller gets the error immediately.
+func (w *watcher) start(ctx context.Context, resourceVersion string) error {
+	var initial []WatchEvent
+	if resourceVersion == "" {
+		var err error
+		initial, resourceVersion, err = w.relist(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	go w.run(ctx, resourceVersion, initial)
+	return nil
+}
+
+func (w *watcher) run(ctx context.Context, resourceVersion string, initial []WatchEvent) {
+	defer close(w.events)
+
+	for _, e := range initial {
+		if !w.send(ctx, e) {
+			return
+		}
+	}
+
+	backoff := w.backoff
+	failures := 0
+	needsRelist := false
+	for {
+		received := false
+		var err error
+
+		// resourceVersion is too old, we need to list again. If the list
+		// fails, we keep trying, watching from "" would skip the changes
+		// in the gap.
+		if needsRelist {
+			var missed []WatchEvent
+			var listed string
+			missed, listed, err = w.relist(ctx)
+			if err == nil {
+				resourceVersion, needsRelist = listed, false
+				for _, e := range missed {
+					if !w.send(ctx, e) {
+						return
+					}
+				}
+			}
+		}
+
+		if !needsRelist {
+			var wi watch.Interface
+			wi, err = w.dr.Watch(ctx, metav1.ListOptions{
+				LabelSelector:       w.labelSelector,
+				FieldSelector:       w.fieldSelector,
+				ResourceVersion:     resourceVersion,
+				AllowWatchBookmarks: true,
+			})
+
+			if err == nil {
+				resourceVersion, received, err = w.consume(ctx, wi, resourceVersion)
+				wi.Stop()
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		// healthy watch, start counting from scratch
+		if received {
+			backoff = w.backoff
+			failures = 0
+		}
+
+		if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+			needsRelist = true
+			continue
+		}
+
+		if err != nil {
+			failures++
+			if !isTransientWatchError(err) || failures > maxWatchRetries {
+				w.send(ctx, WatchEvent{Type: watch.Error, Err: err})
+				return
+			}
+		}
+
+		// server closed the watch without events or transient error, we
+		// don't want to hammer the API server with reconnects
+		if err != nil || !received {
+			if !w.sleep(ctx, backoff.Step()) {
+				return
+			}
+		}
+	}
+}
+
+// returns true for errors which are worth to retry
+func isTransientWatchError(err error) bool {
+	return apierrors.IsTimeout(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsUnexpectedServerError(err) ||
+		utilnet.IsConnectionRefused(err) ||
+		utilnet.IsConnectionReset(err) ||
+		utilnet.IsProbableEOF(err)
+}
+
+// wait for given duration. Returns false if context is cancelled.
+func (w *watcher) sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// read events from watch until it's closed. Function returns last seen
+// resourceVersion, if some event was received and error if server sent
+// error event.
+func (w *watcher) consume(ctx context.Context, wi watch.Interface, resourceVersion string) (string, bool, error) {
+	received := false
+	for {
+		select {
+		case <-ctx.Done():
+			return resourceVersion, received, nil
+
+		case e, ok := <-wi.ResultChan():
+			if !ok {
+				return resourceVersion, received, nil
+			}
+
+			if e.Type == watch.Error {
+				return resourceVersion, received, apierrors.FromObject(e.Object)
+			}
+
+			obj, ok := e.Object.(*unstructured.Unstructured)
+			if !ok {
+				return resourceVersion, received, fmt.Errorf("unexpected object %T in watch", e.Object)
+			}
+			resourceVersion = obj.GetResourceVersion()
+			received = true
+
+			switch e.Type {
+			case watch.Bookmark:
+				continue
+			case watch.Deleted:
+				delete(w.known, watchKey(obj))
+			default:
+				w.known[watchKey(obj)] = obj
+			}
+
+			if !w.send(ctx, WatchEvent{Type: e.Type, Object: obj}) {
+				return resourceVersion, received, nil
+			}
+		}
+	}
+}
+
+// list objects and compare them with known objects. Function returns events
+// for all changes since the last known state and list's resourceVersion.
+func (w *watcher) relist(ctx context.Context) ([]WatchEvent, string, error) {
+	list, err := w.dr.List(ctx, metav1.ListOptions{
+		LabelSelector: w.labelSelector,
+		FieldSelector: w.fieldSelector,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	events := make([]WatchEvent, 0)
+	current := make(map[string]*unstructured.Unstructured)
+	for i := range list.Items {
+		obj := &list.Items[i]
+		key := watchKey(obj)
+		current[key] = obj
+
+		known, ok := w.known[key]
+		switch {
+		case !ok:
+			events = append(events, WatchEvent{Type: watch.Added, Object: obj})
+		case known.GetResourceVersion() != obj.GetResourceVersion():
+			events = append(events, WatchEvent{Type: watch.Modified, Object: obj})
+		}
+	}
+
+	for key, obj := range w.known {
+		if _, ok := current[key]; !ok {
+			events = append(events, WatchEvent{Type: watch.Deleted, Object: obj})
+		}
+	}
+
+	w.known = current
+	return events, list.GetResourceVersion(), nil
+}
+
+// send event to channel. Returns false if context is cancelled.
+func (w *watcher) send(ctx context.Context, e WatchEvent) bool {
+	select {
+	case w.events <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func watchKey(obj *unstructured.Unstructured) string {
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
@@ -0,0 +1,221 @@
+package k8t
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var configMapsGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+func newTestConfigMap(name, resourceVersion string) *unstructured.Unstructured {
+	obj := newTestObject("v1", "ConfigMap", name)
+	obj.SetNamespace("test")
+	obj.SetResourceVersion(resourceVersion)
+	return obj
+}
+
+func Test_watcher(t *testing.T) {
+	// GIVEN: existing config map
+	dyn := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), newTestConfigMap("existing", "1"))
+	dr := dyn.Resource(configMapsGVR).Namespace("test")
+
+	// AND: watch where I control the events
+	fakeWatch := watch.NewFake()
+	dyn.PrependWatchReactor("configmaps", k8stesting.DefaultWatchReactor(fakeWatch, nil))
+
+	// WHEN: I start watching
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := newWatcher(dr, "", "")
+	err := w.start(ctx, "")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: I get the existing config map as added
+	e := receiveEvent(t, w.events)
+	if e.Type != watch.Added || e.Object.GetName() != "existing" {
+		t.FailNow()
+	}
+
+	// WHEN: new config map is created
+	go fakeWatch.Add(newTestConfigMap("created", "2"))
+
+	// THEN: I get the event
+	e = receiveEvent(t, w.events)
+	if e.Type != watch.Added || e.Object.GetName() != "created" {
+		t.FailNow()
+	}
+
+	// WHEN: I stop the watch
+	cancel()
+
+	// THEN: the channel is closed
+	select {
+	case _, ok := <-w.events:
+		if ok {
+			t.FailNow()
+		}
+	case <-time.After(5 * time.Second):
+		t.FailNow()
+	}
+}
+
+func Test_watcher_relist(t *testing.T) {
+	// GIVEN: watcher which knows two config maps
+	dyn := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newTestConfigMap("modified", "2"),
+		newTestConfigMap("added", "3"),
+	)
+
+	w := newWatcher(dyn.Resource(configMapsGVR).Namespace("test"), "", "")
+	w.known["test/modified"] = newTestConfigMap("modified", "1")
+	w.known["test/deleted"] = newTestConfigMap("deleted", "1")
+
+	// WHEN: I re-list the config maps
+	events, _, err := w.relist(context.Background())
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: I get all the missed changes
+	types := map[string]watch.EventType{}
+	for _, e := range events {
+		types[e.Object.GetName()] = e.Type
+	}
+
+	if len(types) != 3 || types["added"] != watch.Added || types["modified"] != watch.Modified || types["deleted"] != watch.Deleted {
+		t.FailNow()
+	}
+}
+
+func Test_watcher_relistRetry(t *testing.T) {
+	// GIVEN: API server where resourceVersion expired and first list fails
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		configMapsGVR: "ConfigMapList",
+	})
+
+	fakeWatch := watch.NewFake()
+	watches := 0
+	dyn.PrependWatchReactor("configmaps", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		watches++
+		if watches == 1 {
+			return true, nil, apierrors.NewResourceExpired("too old resource version")
+		}
+		return true, fakeWatch, nil
+	})
+
+	lists := 0
+	dyn.PrependReactor("list", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		lists++
+		if lists == 1 {
+			return true, nil, apierrors.NewServiceUnavailable("api server is restarting")
+		}
+		return false, nil, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// AND: watcher which knows config map deleted in the meantime
+	w := newWatcher(dyn.Resource(configMapsGVR).Namespace("test"), "", "")
+	w.backoff = wait.Backoff{Duration: time.Millisecond, Steps: 1}
+	w.known["test/deleted"] = newTestConfigMap("deleted", "1")
+
+	// WHEN: I start watching from known resourceVersion
+	err := w.start(ctx, "1")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: the list is retried and I get the missed deletion
+	e := receiveEvent(t, w.events)
+	if e.Type != watch.Deleted || e.Object.GetName() != "deleted" || lists != 2 {
+		t.FailNow()
+	}
+
+	// AND: watching continues only after successful list
+	go fakeWatch.Add(newTestConfigMap("created", "2"))
+	e = receiveEvent(t, w.events)
+	if e.Type != watch.Added || e.Object.GetName() != "created" || watches != 2 {
+		t.FailNow()
+	}
+}
+
+func Test_watcher_retry(t *testing.T) {
+	// GIVEN: API server which fails twice with transient error
+	dyn := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	fakeWatch := watch.NewFake()
+	calls := 0
+	dyn.PrependWatchReactor("configmaps", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		calls++
+		if calls <= 2 {
+			return true, nil, apierrors.NewServiceUnavailable("api server is restarting")
+		}
+		return true, fakeWatch, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := newWatcher(dyn.Resource(configMapsGVR).Namespace("test"), "", "")
+	w.backoff = wait.Backoff{Duration: time.Millisecond, Steps: 1}
+
+	// WHEN: I start watching from known resourceVersion
+	err := w.start(ctx, "1")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: the watch is retried and I get events
+	go fakeWatch.Add(newTestConfigMap("created", "2"))
+	e := receiveEvent(t, w.events)
+	if e.Type != watch.Added || e.Object.GetName() != "created" || calls != 3 {
+		t.FailNow()
+	}
+}
+
+func Test_watcher_permanentError(t *testing.T) {
+	// GIVEN: API server which forbids the watch
+	dyn := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	dyn.PrependWatchReactor("configmaps", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		return true, nil, apierrors.NewForbidden(configMapsGVR.GroupResource(), "", errors.New("no access"))
+	})
+
+	w := newWatcher(dyn.Resource(configMapsGVR).Namespace("test"), "", "")
+	w.backoff = wait.Backoff{Duration: time.Millisecond, Steps: 1}
+
+	// WHEN: I start watching from known resourceVersion
+	err := w.start(context.Background(), "1")
+	if err != nil {
+		t.FailNow()
+	}
+
+	// THEN: I get error event without retries
+	e := receiveEvent(t, w.events)
+	if e.Type != watch.Error || !apierrors.IsForbidden(e.Err) {
+		t.FailNow()
+	}
+}
+
+func receiveEvent(t *testing.T, events <-chan WatchEvent) WatchEvent {
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(5 * time.Second):
+		t.FailNow()
+		return WatchEvent{}
+	}
+}
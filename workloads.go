@@ -0,0 +1,371 @@
+package k8t
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// check if given deployment is rolled out and available. The rules are the
+// same as 'kubectl rollout status' uses: the controller observed the latest
+// generation, all replicas are updated, old replicas are terminated and
+// all updated replicas are available. If you provide empty namespace, then
+// cluster's default test namespace will be used. When some pod of the
+// deployment's newest ReplicaSet is stuck (e.g. 'ImagePullBackOff' or
+// 'CrashLoopBackOff'), the checker returns error wrapping ErrTerminal. Pods
+// of older ReplicaSets are ignored, the rollout replaces them.
+func DeploymentIsAvailable(namespace, name string) Checker {
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		deployment, err := c.k8sClient.AppsV1().Deployments(c.namespaceOrDefault(namespace)).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		ok, err := deploymentIsAvailable(deployment)
+		if ok || err != nil {
+			return ok, err
+		}
+		hash, err := c.newestReplicaSetHash(ctx, deployment)
+		if err != nil {
+			return false, err
+		}
+		return false, c.workloadPodsError(ctx, "deployment", deployment.ObjectMeta, deployment.Spec.Selector, appsv1.DefaultDeploymentUniqueLabelKey, hash)
+	}
+}
+
+// check if given statefulset is rolled out and all replicas are ready,
+// in the same way as 'kubectl rollout status'. If you provide empty
+// namespace, then cluster's default test namespace will be used. Stuck pods
+// of the update revision are reported as error wrapping ErrTerminal.
+func StatefulSetIsReady(namespace, name string) Checker {
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		sts, err := c.k8sClient.AppsV1().StatefulSets(c.namespaceOrDefault(namespace)).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		ok, err := statefulSetIsReady(sts)
+		if ok || err != nil {
+			return ok, err
+		}
+		return false, c.workloadPodsError(ctx, "statefulset", sts.ObjectMeta, sts.Spec.Selector, appsv1.ControllerRevisionHashLabelKey, sts.Status.UpdateRevision)
+	}
+}
+
+// check if given daemonset is rolled out and pods are available on all
+// desired nodes, in the same way as 'kubectl rollout status'. If you provide
+// empty namespace, then cluster's default test namespace will be used.
+// Stuck pods of the newest revision are reported as error wrapping
+// ErrTerminal.
+func DaemonSetIsReady(namespace, name string) Checker {
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		ds, err := c.k8sClient.AppsV1().DaemonSets(c.namespaceOrDefault(namespace)).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		ok, err := daemonSetIsReady(ds)
+		if ok || err != nil {
+			return ok, err
+		}
+		hash, err := c.newestControllerRevisionHash(ctx, ds)
+		if err != nil {
+			return false, err
+		}
+		return false, c.workloadPodsError(ctx, "daemonset", ds.ObjectMeta, ds.Spec.Selector, appsv1.ControllerRevisionHashLabelKey, hash)
+	}
+}
+
+// check if given job succeeded, that means it has 'Complete' condition. If
+// the job failed (e.g. exceeded its backoff limit), the checker returns
+// error wrapping ErrTerminal, because it cannot succeed anymore. If you
+// provide empty namespace, then cluster's default test namespace will be
+// used.
+func JobSucceeded(namespace, name string) Checker {
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		job, err := c.k8sClient.BatchV1().Jobs(c.namespaceOrDefault(namespace)).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return jobSucceeded(job)
+	}
+}
+
+// check if given job failed, that means it has 'Failed' condition. If
+// the job succeeded, the checker returns error, because it cannot fail
+// anymore. If you provide empty namespace, then cluster's default test
+// namespace will be used.
+func JobFailed(namespace, name string) Checker {
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		job, err := c.k8sClient.BatchV1().Jobs(c.namespaceOrDefault(namespace)).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return jobFailed(job)
+	}
+}
+
+// check if given pod is ready, that means it has 'Ready' condition, all
+// containers are running and passing readiness probes. The Running phase
+// alone doesn't mean the containers are ready. If you provide empty
+// namespace, then cluster's default test namespace will be used. Pods which
+// cannot start are reported as error wrapping ErrTerminal.
+func PodIsReady(namespace, name string) Checker {
+	return func(ctx context.Context, c *Cluster) (bool, error) {
+		pod, err := c.k8sClient.CoreV1().Pods(c.namespaceOrDefault(namespace)).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return podIsReady(pod)
+	}
+}
+
+// list pods of the workload's current revision and returns error wrapping
+// ErrTerminal if some of them is stuck. The revision is matched by given
+// label, pods of older revisions are replaced by rollout. If the revision
+// is not known yet, nothing is checked. Failed pods are not terminal for
+// workloads, controller replaces them.
+func (c *Cluster) workloadPodsError(ctx context.Context, kind string, meta metav1.ObjectMeta, selector *metav1.LabelSelector, revisionLabel, revision string) error {
+	if selector == nil || revision == "" {
+		return nil
+	}
+
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return err
+	}
+
+	pods, err := c.k8sClient.CoreV1().Pods(meta.Namespace).List(ctx, metav1.ListOptions{LabelSelector: sel.String()})
+	if err != nil {
+		return err
+	}
+
+	for i := range pods.Items {
+		if pods.Items[i].Labels[revisionLabel] != revision {
+			continue
+		}
+
+		if err := containerTerminalError(&pods.Items[i]); err != nil {
+			return fmt.Errorf("%s %s: %w", kind, meta.Name, err)
+		}
+	}
+	return nil
+}
+
+// returns 'pod-template-hash' of deployment's newest ReplicaSet, or empty
+// string if there is no ReplicaSet yet
+func (c *Cluster) newestReplicaSetHash(ctx context.Context, d *appsv1.Deployment) (string, error) {
+	sel, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+
+	rsList, err := c.k8sClient.AppsV1().ReplicaSets(d.Namespace).List(ctx, metav1.ListOptions{LabelSelector: sel.String()})
+	if err != nil {
+		return "", err
+	}
+
+	hash, newest := "", int64(-1)
+	for i := range rsList.Items {
+		rs := &rsList.Items[i]
+		if !metav1.IsControlledBy(rs, d) {
+			continue
+		}
+
+		revision, err := strconv.ParseInt(rs.Annotations["deployment.kubernetes.io/revision"], 10, 64)
+		if err != nil || revision <= newest {
+			continue
+		}
+		hash, newest = rs.Labels[appsv1.DefaultDeploymentUniqueLabelKey], revision
+	}
+	return hash, nil
+}
+
+// returns 'controller-revision-hash' of daemonset's newest ControllerRevision,
+// or empty string if there is no revision yet
+func (c *Cluster) newestControllerRevisionHash(ctx context.Context, ds *appsv1.DaemonSet) (string, error) {
+	sel, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+
+	revisions, err := c.k8sClient.AppsV1().ControllerRevisions(ds.Namespace).List(ctx, metav1.ListOptions{LabelSelector: sel.String()})
+	if err != nil {
+		return "", err
+	}
+
+	hash, newest := "", int64(-1)
+	for i := range revisions.Items {
+		rev := &revisions.Items[i]
+		if !metav1.IsControlledBy(rev, ds) || rev.Revision <= newest {
+			continue
+		}
+		hash, newest = rev.Labels[appsv1.ControllerRevisionHashLabelKey], rev.Revision
+	}
+	return hash, nil
+}
+
+// returns given namespace, or cluster's test namespace if it's empty
+func (c *Cluster) namespaceOrDefault(namespace string) string {
+	if namespace == "" {
+		return c.testNamespace
+	}
+	return namespace
+}
+
+func deploymentIsAvailable(d *appsv1.Deployment) (bool, error) {
+	if d.Generation > d.Status.ObservedGeneration {
+		return false, nil
+	}
+
+	for _, cond := range d.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Reason == "ProgressDeadlineExceeded" {
+			return false, Terminalf("deployment %s exceeded its progress deadline: %s", d.Name, cond.Message)
+		}
+	}
+
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+
+	switch {
+	case d.Status.UpdatedReplicas < replicas:
+		return false, nil
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		return false, nil
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+func statefulSetIsReady(sts *appsv1.StatefulSet) (bool, error) {
+	if sts.Status.ObservedGeneration == 0 || sts.Generation > sts.Status.ObservedGeneration {
+		return false, nil
+	}
+
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+
+	if sts.Status.ReadyReplicas < replicas {
+		return false, nil
+	}
+
+	// rolling update can be partitioned, only pods above partition are updated
+	if sts.Spec.UpdateStrategy.Type == appsv1.RollingUpdateStatefulSetStrategyType && sts.Spec.UpdateStrategy.RollingUpdate != nil {
+		partition := sts.Spec.UpdateStrategy.RollingUpdate.Partition
+		if partition != nil && *partition > 0 {
+			return sts.Status.UpdatedReplicas >= replicas-*partition, nil
+		}
+	}
+
+	// pods are not updated automatically with OnDelete strategy
+	if sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return true, nil
+	}
+
+	return sts.Status.UpdateRevision == sts.Status.CurrentRevision, nil
+}
+
+func daemonSetIsReady(ds *appsv1.DaemonSet) (bool, error) {
+	if ds.Generation > ds.Status.ObservedGeneration {
+		return false, nil
+	}
+
+	switch {
+	case ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled:
+		return false, nil
+	case ds.Status.NumberAvailable < ds.Status.DesiredNumberScheduled:
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+func jobSucceeded(job *batchv1.Job) (bool, error) {
+	if cond := jobCondition(job, batchv1.JobFailed); cond != nil {
+		return false, Terminalf("job %s failed: %s: %s", job.Name, cond.Reason, cond.Message)
+	}
+	return jobHasCondition(job, batchv1.JobComplete), nil
+}
+
+func jobFailed(job *batchv1.Job) (bool, error) {
+	if jobHasCondition(job, batchv1.JobComplete) {
+		return false, Terminalf("job %s succeeded", job.Name)
+	}
+	return jobHasCondition(job, batchv1.JobFailed), nil
+}
+
+func jobHasCondition(job *batchv1.Job, condType batchv1.JobConditionType) bool {
+	return jobCondition(job, condType) != nil
+}
+
+// returns job's condition of given type with 'True' status, or nil
+func jobCondition(job *batchv1.Job, condType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		cond := &job.Status.Conditions[i]
+		if cond.Type == condType && cond.Status == corev1.ConditionTrue {
+			return cond
+		}
+	}
+	return nil
+}
+
+func podIsReady(pod *corev1.Pod) (bool, error) {
+	if err := podTerminalError(pod); err != nil {
+		return false, err
+	}
+
+	if pod.Status.Phase != corev1.PodRunning {
+		return false, nil
+	}
+
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue, nil
+		}
+	}
+	return false, nil
+}
+
+// container waiting reasons which are not expected to resolve without
+// change of the pod
+var terminalWaitingReasons = map[string]bool{
+	"ImagePullBackOff":           true,
+	"ErrImageNeverPull":          true,
+	"InvalidImageName":           true,
+	"CrashLoopBackOff":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+// returns error wrapping ErrTerminal if the pod failed, or some of its
+// containers is stuck in terminal waiting state. Otherwise returns nil.
+func podTerminalError(pod *corev1.Pod) error {
+	if pod.Status.Phase == corev1.PodFailed {
+		return Terminalf("pod %s failed: %s: %s", pod.Name, pod.Status.Reason, pod.Status.Message)
+	}
+	return containerTerminalError(pod)
+}
+
+// returns error wrapping ErrTerminal if some of pod's containers is stuck
+// in terminal waiting state. Otherwise returns nil.
+func containerTerminalError(pod *corev1.Pod) error {
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		waiting := status.State.Waiting
+		if waiting != nil && terminalWaitingReasons[waiting.Reason] {
+			return Terminalf("pod %s container %s is waiting: %s: %s", pod.Name, status.Name, waiting.Reason, waiting.Message)
+		}
+	}
+
+	return nil
+}
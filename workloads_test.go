@@ -0,0 +1,286 @@
+package k8t
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func Test_deploymentIsAvailable(t *testing.T) {
+	// GIVEN: deployment with 3 replicas in the middle of rollout
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           4,
+			UpdatedReplicas:    3,
+			AvailableReplicas:  3,
+		},
+	}
+
+	// THEN: it's not available while old replica is still running
+	if ok, err := deploymentIsAvailable(d); ok || err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: old replica is terminated
+	d.Status.Replicas = 3
+
+	// THEN: it's available
+	if ok, err := deploymentIsAvailable(d); !ok || err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: new generation is not observed yet
+	d.Generation = 3
+
+	// THEN: it's not available
+	if ok, _ := deploymentIsAvailable(d); ok {
+		t.FailNow()
+	}
+
+	// WHEN: progress deadline is exceeded
+	d.Status.ObservedGeneration = 3
+	d.Status.Conditions = []appsv1.DeploymentCondition{
+		{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+	}
+
+	// THEN: checker ends with terminal error
+	if _, err := deploymentIsAvailable(d); !errors.Is(err, ErrTerminal) {
+		t.FailNow()
+	}
+}
+
+func Test_statefulSetIsReady(t *testing.T) {
+	// GIVEN: statefulset with all replicas ready, but not updated yet
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Generation: 1},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:       int32Ptr(2),
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+		},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 1,
+			ReadyReplicas:      2,
+			CurrentRevision:    "db-1",
+			UpdateRevision:     "db-2",
+		},
+	}
+
+	// THEN: it's not ready
+	if ok, _ := statefulSetIsReady(sts); ok {
+		t.FailNow()
+	}
+
+	// WHEN: all replicas are updated
+	sts.Status.CurrentRevision = "db-2"
+
+	// THEN: it's ready
+	if ok, _ := statefulSetIsReady(sts); !ok {
+		t.FailNow()
+	}
+}
+
+func Test_daemonSetIsReady(t *testing.T) {
+	// GIVEN: daemonset where pod is not available on one node
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Generation: 1},
+		Status: appsv1.DaemonSetStatus{
+			ObservedGeneration:     1,
+			DesiredNumberScheduled: 3,
+			UpdatedNumberScheduled: 3,
+			NumberAvailable:        2,
+		},
+	}
+
+	// THEN: it's not ready
+	if ok, _ := daemonSetIsReady(ds); ok {
+		t.FailNow()
+	}
+
+	// WHEN: pods are available on all nodes
+	ds.Status.NumberAvailable = 3
+
+	// THEN: it's ready
+	if ok, _ := daemonSetIsReady(ds); !ok {
+		t.FailNow()
+	}
+}
+
+func Test_jobSucceeded(t *testing.T) {
+	// GIVEN: running job
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job"}}
+
+	// THEN: it neither succeeded nor failed
+	if ok, err := jobSucceeded(job); ok || err != nil {
+		t.FailNow()
+	}
+
+	if ok, err := jobFailed(job); ok || err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: job fails
+	job.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+	}
+
+	// THEN: JobFailed is met and JobSucceeded ends with error
+	if ok, err := jobFailed(job); !ok || err != nil {
+		t.FailNow()
+	}
+
+	if _, err := jobSucceeded(job); !errors.Is(err, ErrTerminal) {
+		t.FailNow()
+	}
+}
+
+func Test_podIsReady(t *testing.T) {
+	// GIVEN: running pod with containers which are not ready yet
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+			},
+		},
+	}
+
+	// THEN: pod is not ready
+	if ok, _ := podIsReady(pod); ok {
+		t.FailNow()
+	}
+
+	// WHEN: containers become ready
+	pod.Status.Conditions[0].Status = corev1.ConditionTrue
+
+	// THEN: pod is ready
+	if ok, _ := podIsReady(pod); !ok {
+		t.FailNow()
+	}
+}
+
+func Test_podTerminalError(t *testing.T) {
+	// GIVEN: pod where container is pulling image
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pod"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+			},
+		},
+	}
+
+	// THEN: it's not terminal
+	if ok, err := podIsReady(pod); ok || err != nil {
+		t.FailNow()
+	}
+
+	// WHEN: image cannot be pulled
+	pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{
+		Reason:  "ImagePullBackOff",
+		Message: "Back-off pulling image \"busybox:nope\"",
+	}
+
+	// THEN: checker ends with terminal error containing the reason
+	_, err := podIsReady(pod)
+	if !errors.Is(err, ErrTerminal) || !strings.Contains(err.Error(), "ImagePullBackOff") {
+		t.FailNow()
+	}
+}
+
+func newStuckTestDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "test", UID: "app-uid", Generation: 2},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(1),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "app"}},
+		},
+		Status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 1},
+	}
+}
+
+func newStuckTestReplicaSet(d *appsv1.Deployment, hash, revision string) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "app-" + hash,
+			Namespace:       "test",
+			Labels:          map[string]string{"app": "app", "pod-template-hash": hash},
+			Annotations:     map[string]string{"deployment.kubernetes.io/revision": revision},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(d, appsv1.SchemeGroupVersion.WithKind("Deployment"))},
+		},
+	}
+}
+
+func newStuckTestPod(hash, reason string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-" + hash + "-1", Namespace: "test", Labels: map[string]string{"app": "app", "pod-template-hash": hash}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	if reason != "" {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}}},
+		}
+	}
+	return pod
+}
+
+func Test_DeploymentIsAvailableStuckPod(t *testing.T) {
+	// GIVEN: deployment which is rolling out, where pod of new replicaset cannot start
+	deployment := newStuckTestDeployment()
+	cluster := &Cluster{
+		k8sClient: fake.NewSimpleClientset(
+			deployment,
+			newStuckTestReplicaSet(deployment, "old", "1"),
+			newStuckTestReplicaSet(deployment, "new", "2"),
+			newStuckTestPod("old", ""),
+			newStuckTestPod("new", "CrashLoopBackOff"),
+		),
+		testNamespace: "test",
+	}
+
+	// WHEN: I check the deployment is available
+	_, err := DeploymentIsAvailable("", "app")(context.Background(), cluster)
+
+	// THEN: checker ends with terminal error
+	if !errors.Is(err, ErrTerminal) || !strings.Contains(err.Error(), "CrashLoopBackOff") {
+		t.Fatal(err)
+	}
+}
+
+func Test_DeploymentIsAvailableStuckOldPod(t *testing.T) {
+	// GIVEN: crash-looping deployment which is being fixed by new replicaset
+	deployment := newStuckTestDeployment()
+	cluster := &Cluster{
+		k8sClient: fake.NewSimpleClientset(
+			deployment,
+			newStuckTestReplicaSet(deployment, "old", "1"),
+			newStuckTestReplicaSet(deployment, "new", "2"),
+			newStuckTestPod("old", "CrashLoopBackOff"),
+			newStuckTestPod("new", ""),
+		),
+		testNamespace: "test",
+	}
+
+	// WHEN: I check the deployment is available
+	ok, err := DeploymentIsAvailable("", "app")(context.Background(), cluster)
+
+	// THEN: it's not available yet, but the old pod is not terminal
+	if ok || err != nil {
+		t.Fatal(err)
+	}
+}